@@ -1,3 +1,5 @@
 module github.com/bored-engineer/crypto
 
+go 1.24
+
 require golang.org/x/sys v0.0.0-20190215142949-d0b11bdaac8a
@@ -0,0 +1,33 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+)
+
+// DeriveAEAD expands an AEAD key and nonce from the pseudorandom key and
+// optional context info. Both are read from a single expansion of
+// keyLen+nonceLen bytes: the key is the first keyLen bytes, and the nonce the
+// nonceLen bytes that follow, which is the order used by protocols that derive
+// them together.
+//
+// It returns ErrEntropyLimit if keyLen+nonceLen exceeds the amount of key
+// material that can be derived with hash.
+func DeriveAEAD(hash func() hash.Hash, prk, info []byte, keyLen, nonceLen int) (key, nonce []byte, err error) {
+	if keyLen < 0 || nonceLen < 0 {
+		return nil, nil, errors.New("hkdf: negative output length")
+	}
+	limit := MaxOutputLen(hash)
+	if keyLen > limit || nonceLen > limit-keyLen {
+		return nil, nil, ErrEntropyLimit
+	}
+	out := make([]byte, keyLen+nonceLen)
+	if err := ExpandInto(hash, prk, info, out); err != nil {
+		return nil, nil, err
+	}
+	return out[:keyLen:keyLen], out[keyLen:], nil
+}
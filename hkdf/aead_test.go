@@ -0,0 +1,48 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestDeriveAEAD(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("aead")
+
+	expected := make([]byte, 44)
+	io.ReadFull(Expand(sha256.New, prk, info), expected)
+
+	key, nonce, err := DeriveAEAD(sha256.New, prk, info, 32, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(key, expected[:32]) {
+		t.Errorf("incorrect key: have %x, need %x", key, expected[:32])
+	}
+	if !bytes.Equal(nonce, expected[32:]) {
+		t.Errorf("incorrect nonce: have %x, need %x", nonce, expected[32:])
+	}
+
+	// Appending to the key must not overwrite the nonce
+	_ = append(key, 0xff)
+	if !bytes.Equal(nonce, expected[32:]) {
+		t.Errorf("nonce overwritten by appending to the key: %x", nonce)
+	}
+
+	limit := MaxOutputLen(sha256.New)
+	if _, _, err := DeriveAEAD(sha256.New, prk, info, limit-12, 12); err != nil {
+		t.Errorf("unexpected error at the entropy limit: %v", err)
+	}
+	if _, _, err := DeriveAEAD(sha256.New, prk, info, limit-11, 12); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, _, err := DeriveAEAD(sha256.New, prk, info, 32, -1); err == nil {
+		t.Errorf("expected an error for a negative length")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"io"
+)
+
+// Array is the set of fixed-size key types that can be derived with
+// ReadArray and KeyArray.
+type Array interface {
+	~[16]byte | ~[24]byte | ~[32]byte | ~[48]byte | ~[64]byte
+}
+
+// ReadArray reads exactly len(T) bytes from r and returns them as a T. It
+// returns an error if fewer bytes could be read, such as ErrEntropyLimit when r
+// is a Reader that has reached its limit.
+func ReadArray[T Array](r io.Reader) (T, error) {
+	var key T
+	var buf [64]byte
+	b := buf[:len(key)]
+	if _, err := io.ReadFull(r, b); err != nil {
+		return key, err
+	}
+	for i := range b {
+		key[i] = b[i]
+		b[i] = 0
+	}
+	return key, nil
+}
+
+// KeyArray is like Key, but derives exactly len(T) bytes and returns them as a
+// T, so that the key length is checked at compile time.
+func KeyArray[T Array](hash func() hash.Hash, secret, salt, info []byte) (T, error) {
+	return ReadArray[T](New(hash, secret, salt, info))
+}
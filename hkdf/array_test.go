@@ -0,0 +1,44 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestKeyArray(t *testing.T) {
+	secret, salt, info := []byte("secret"), []byte("salt"), []byte("info")
+	expected := make([]byte, 64)
+	io.ReadFull(New(sha256.New, secret, salt, info), expected)
+
+	k16, err := KeyArray[[16]byte](sha256.New, secret, salt, info)
+	if err != nil || !bytes.Equal(k16[:], expected[:16]) {
+		t.Errorf("16 byte key: have %x, %v, need %x", k16, err, expected[:16])
+	}
+	k32, err := KeyArray[[32]byte](sha256.New, secret, salt, info)
+	if err != nil || !bytes.Equal(k32[:], expected[:32]) {
+		t.Errorf("32 byte key: have %x, %v, need %x", k32, err, expected[:32])
+	}
+	type key64 [64]byte
+	k64, err := KeyArray[key64](sha256.New, secret, salt, info)
+	if err != nil || !bytes.Equal(k64[:], expected) {
+		t.Errorf("64 byte key: have %x, %v, need %x", k64, err, expected)
+	}
+}
+
+func TestReadArrayShort(t *testing.T) {
+	if _, err := ReadArray[[32]byte](bytes.NewReader(make([]byte, 31))); err != io.ErrUnexpectedEOF {
+		t.Errorf("short stream: have %v, need %v", err, io.ErrUnexpectedEOF)
+	}
+
+	hkdf := New(sha256.New, []byte("secret"), nil, nil)
+	io.ReadFull(hkdf, make([]byte, hkdf.Remaining()-16))
+	if _, err := ReadArray[[32]byte](hkdf); err != ErrEntropyLimit {
+		t.Errorf("exhausted reader: have %v, need %v", err, ErrEntropyLimit)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+// SetAudit sets a hook that f calls with the one-based index of each block of
+// output keying material, right after the block is generated, so that the
+// amount of key material produced can be recorded. The hook never receives
+// the output itself. Blocks generated by Skip are reported too, as they are
+// computed, and the hook is kept by Reset and Clone.
+//
+// A nil hook disables auditing. The hook is called synchronously by the
+// method generating the block, and must not use f.
+func (f *Reader) SetAudit(hook func(blockIndex byte)) {
+	f.audit = hook
+}
@@ -0,0 +1,41 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestSetAudit(t *testing.T) {
+	var blocks []byte
+	hkdf := New(sha256.New, []byte("secret"), nil, nil)
+	hkdf.SetAudit(func(blockIndex byte) { blocks = append(blocks, blockIndex) })
+
+	io.ReadFull(hkdf, make([]byte, 40))
+	if err := hkdf.Skip(30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if need := []byte{1, 2, 3}; !reflect.DeepEqual(blocks, need) {
+		t.Errorf("have blocks %v, need %v", blocks, need)
+	}
+
+	blocks = nil
+	hkdf.Rewind()
+	io.ReadFull(hkdf.Clone(), make([]byte, 1))
+	io.ReadFull(hkdf, make([]byte, 1))
+	if need := []byte{1, 1}; !reflect.DeepEqual(blocks, need) {
+		t.Errorf("have blocks %v after Rewind and Clone, need %v", blocks, need)
+	}
+
+	blocks = nil
+	hkdf.SetAudit(nil)
+	io.ReadFull(hkdf, make([]byte, 100))
+	if len(blocks) != 0 {
+		t.Errorf("disabled hook was called for blocks %v", blocks)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"sync"
+	"time"
+)
+
+// A Cache stores derived keys, so that a service deriving the same keys
+// over and over computes each of them once. Concurrent requests for the same
+// key, including ones that are not stored, are deduplicated, so that only one
+// of them derives it while the others wait for the result.
+//
+// The zero value is an empty Cache, which deduplicates requests but does not
+// store keys. A Cache is safe for concurrent use, and must not be copied after
+// first use.
+//
+// A Cache holds the derived keys in memory until they expire, and keys them by
+// a hash of the secret, salt and info of each request, which reveals whether
+// two requests used the same inputs. Keys are removed and overwritten with
+// zeros as soon as they expire or are cleared, but the copies returned by Get
+// are owned by the callers. Only use a Cache where this is acceptable, and
+// keep the TTL as short as possible.
+type Cache struct {
+	// TTL is how long a derived key is stored after it is computed. If it is
+	// zero or negative, keys are not stored.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*cacheEntry
+}
+
+// A cacheEntry is a key of a Cache, stored or being computed. Its fields
+// other than done are guarded by the mutex of the Cache.
+type cacheEntry struct {
+	done  chan struct{}
+	key   []byte
+	err   error
+	timer *time.Timer
+
+	// refs is the number of calls to Get that still have to copy key, which
+	// is only overwritten once it is zero and the entry has been removed.
+	refs    int
+	removed bool
+}
+
+// cacheLabel is hashed with each hash passed to Get, so that hashes that
+// compute different functions have different cache entries.
+var cacheLabel = []byte("hkdf cache")
+
+// Get returns a copy of the key of length bytes derived with DeriveKey from
+// the given hash, secret, salt and info, computing it only if it is not stored
+// in c and no other call is already computing it. Errors are returned to every
+// concurrent caller, but are not stored.
+func (c *Cache) Get(hash func() hash.Hash, secret, salt, info []byte, length int) ([]byte, error) {
+	id := cacheID(hash, secret, salt, info, length)
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[[sha256.Size]byte]*cacheEntry)
+	}
+	if e, ok := c.entries[id]; ok {
+		e.refs++
+		c.mu.Unlock()
+		<-e.done
+		return c.result(e)
+	}
+	e := &cacheEntry{done: make(chan struct{}), refs: 1}
+	c.entries[id] = e
+	c.mu.Unlock()
+
+	// If DeriveKey panics, for example for an invalid hash, the waiting
+	// calls must not wait forever.
+	derived := false
+	defer func() {
+		if !derived {
+			c.mu.Lock()
+			e.err = errors.New("hkdf: key derivation panicked")
+			c.remove(id, e)
+			c.mu.Unlock()
+			close(e.done)
+		}
+	}()
+	key, err := DeriveKey(hash, secret, salt, info, length)
+	derived = true
+
+	c.mu.Lock()
+	e.key, e.err = key, err
+	if err != nil || c.TTL <= 0 {
+		c.remove(id, e)
+	} else {
+		e.timer = time.AfterFunc(c.TTL, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.remove(id, e)
+		})
+	}
+	c.mu.Unlock()
+	close(e.done)
+
+	return c.result(e)
+}
+
+// Clear removes every key stored in c, and overwrites it with zeros.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, e := range c.entries {
+		select {
+		case <-e.done:
+			e.timer.Stop()
+			c.remove(id, e)
+		default:
+			// Keep the keys being computed, so that their waiters find
+			// them.
+		}
+	}
+}
+
+// result returns a copy of the key of the complete entry e, or its error, and
+// releases the reference to e of the caller.
+func (c *Cache) result(e *cacheEntry) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var key []byte
+	if e.err == nil {
+		key = append([]byte(nil), e.key...)
+	}
+	e.refs--
+	if e.removed && e.refs == 0 {
+		e.wipe()
+	}
+	return key, e.err
+}
+
+// remove removes e from c, and overwrites its key if no call to Get still has
+// to copy it. It must be called with c.mu held.
+func (c *Cache) remove(id [sha256.Size]byte, e *cacheEntry) {
+	if c.entries[id] == e {
+		delete(c.entries, id)
+	}
+	e.removed = true
+	if e.refs == 0 {
+		e.wipe()
+	}
+}
+
+func (e *cacheEntry) wipe() {
+	for i := range e.key {
+		e.key[i] = 0
+	}
+}
+
+// cacheID returns the identifier of the entry of a Cache for the given
+// inputs. The inputs are framed by Info, so that different inputs cannot have
+// the same encoding.
+func cacheID(h func() hash.Hash, secret, salt, info []byte, length int) [sha256.Size]byte {
+	fingerprint := h()
+	fingerprint.Write(cacheLabel)
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(length))
+	return sha256.Sum256(Info(fingerprint.Sum(nil), secret, salt, info, n[:]))
+}
@@ -0,0 +1,191 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bored-engineer/crypto/sha3"
+)
+
+// countingHash counts the extractions from secret, and blocks them until
+// release is closed, if it is not nil.
+type countingHash struct {
+	hash.Hash
+	secret  []byte
+	count   *int32
+	release chan struct{}
+}
+
+func (h countingHash) Write(p []byte) (int, error) {
+	if bytes.Equal(p, h.secret) {
+		atomic.AddInt32(h.count, 1)
+		if h.release != nil {
+			<-h.release
+		}
+	}
+	return h.Hash.Write(p)
+}
+
+func TestCache(t *testing.T) {
+	secret, salt, info := []byte("secret"), []byte("salt"), []byte("info")
+	var count int32
+	h := func() hash.Hash { return countingHash{Hash: sha256.New(), secret: secret, count: &count} }
+
+	c := &Cache{TTL: time.Hour}
+	expected, _ := DeriveKey(sha256.New, secret, salt, info, 42)
+	for i := 0; i < 3; i++ {
+		key, err := c.Get(h, secret, salt, info, 42)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(key, expected) {
+			t.Errorf("have %x, need %x", key, expected)
+		}
+		key[0] ^= 0xff
+	}
+	if count != 1 {
+		t.Errorf("key derived %d times, need 1", count)
+	}
+
+	// Different inputs are different entries
+	for _, get := range []func() ([]byte, error){
+		func() ([]byte, error) { return c.Get(h, secret, salt, info, 41) },
+		func() ([]byte, error) { return c.Get(h, secret, nil, info, 42) },
+		func() ([]byte, error) { return c.Get(h, secret, salt, nil, 42) },
+		func() ([]byte, error) { return c.Get(sha512.New, secret, salt, info, 42) },
+	} {
+		if key, _ := get(); bytes.Equal(key, expected) {
+			t.Errorf("different inputs returned the same key")
+		}
+	}
+
+	stored := storedEntries(c)
+	c.Clear()
+	if n := len(storedEntries(c)); n != 0 {
+		t.Errorf("%d keys stored after Clear", n)
+	}
+	for _, e := range stored {
+		if !bytes.Equal(e.key, make([]byte, len(e.key))) {
+			t.Errorf("key not overwritten by Clear")
+		}
+	}
+	c.Get(h, secret, salt, info, 42)
+	if count != 5 {
+		t.Errorf("key derived %d times after Clear, need 5", count)
+	}
+
+	if _, err := c.Get(sha256.New, secret, nil, nil, MaxOutputLen(sha256.New)+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := c.Get(sha256.New, secret, nil, nil, -1); err == nil {
+		t.Errorf("expected an error for a negative length")
+	}
+
+	// A panicking derivation is not left in the Cache
+	shake := func() hash.Hash { return shakeHash{sha3.NewShake128()} }
+	for i := 0; i < 2; i++ {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for a hash without a fixed size")
+				}
+			}()
+			c.Get(shake, secret, nil, nil, 32)
+		}()
+	}
+	if n := len(storedEntries(c)); n != 1 {
+		t.Errorf("have %d keys stored, need 1", n)
+	}
+}
+
+// storedEntries returns the entries of c.
+func storedEntries(c *Cache) []*cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var entries []*cacheEntry
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestCacheExpiry(t *testing.T) {
+	c := &Cache{TTL: time.Millisecond}
+	for i := 0; i < 10; i++ {
+		c.Get(sha256.New, []byte{byte(i)}, nil, nil, 32)
+	}
+	stored := storedEntries(c)
+
+	// Keys that are never requested again are still removed
+	deadline := time.Now().Add(10 * time.Second)
+	for len(storedEntries(c)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expired keys were not removed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range stored {
+		if !bytes.Equal(e.key, make([]byte, len(e.key))) {
+			t.Errorf("expired key not overwritten")
+		}
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	secret := []byte("secret")
+	var count int32
+	release := make(chan struct{})
+	h := func() hash.Hash {
+		return countingHash{Hash: sha256.New(), secret: secret, count: &count, release: release}
+	}
+
+	// The derivation blocks until every goroutine has been started, and
+	// the ones that arrive after it completes find the stored key, so it
+	// must be computed exactly once.
+	c := &Cache{TTL: time.Hour}
+	expected, _ := DeriveKey(sha256.New, secret, nil, nil, 32)
+	const n = 8
+	var started, wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		started.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			key, err := c.Get(h, secret, nil, nil, 32)
+			if err != nil || !bytes.Equal(key, expected) {
+				t.Errorf("have %x, %v, need %x", key, err, expected)
+			}
+		}()
+	}
+	started.Wait()
+	close(release)
+	wg.Wait()
+
+	if count != 1 {
+		t.Errorf("key derived %d times, need 1", count)
+	}
+
+	// The zero Cache does not store keys, and overwrites them once every
+	// waiting call has copied them
+	var zero Cache
+	key, err := zero.Get(sha256.New, secret, nil, nil, 32)
+	if err != nil || !bytes.Equal(key, expected) {
+		t.Errorf("zero Cache: have %x, %v, need %x", key, err, expected)
+	}
+	if n := len(storedEntries(&zero)); n != 0 {
+		t.Errorf("zero Cache stored %d keys", n)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "hash"
+
+// A Chain is the state of a ratcheting key schedule, in which each step
+// derives a new chain key together with its output. Knowing the chain key of a
+// step does not reveal the outputs of earlier steps. A Chain must not be used
+// concurrently.
+type Chain struct {
+	hash func() hash.Hash
+	key  []byte
+}
+
+// NewChain returns a Chain using hash, whose first chain key is initialSecret.
+func NewChain(hash func() hash.Hash, initialSecret []byte) *Chain {
+	return &Chain{hash: hash, key: append([]byte(nil), initialSecret...)}
+}
+
+// Step advances c, and returns length bytes of output keying material. It
+// extracts a pseudorandom key from input with the current chain key as salt,
+// and expands it with info into a new chain key, as long as the output of
+// hash, followed by the returned output. The previous chain key is overwritten
+// with zeros.
+//
+// Step panics if length is negative or exceeds the amount of key material that can be
+// derived with hash, minus the length of the chain key.
+func (c *Chain) Step(input, info []byte, length int) []byte {
+	if length < 0 {
+		panic("hkdf: negative output length")
+	}
+	prk := Extract(c.hash, input, c.key)
+	out := make([]byte, len(prk)+length)
+	if err := ExpandInto(c.hash, prk, info, out); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	for i := range c.key {
+		c.key[i] = 0
+	}
+	c.key = out[:len(prk):len(prk)]
+	return out[len(prk):]
+}
+
+// String returns a fixed string that does not reveal the chain key of c, so
+// that a Chain can be logged safely.
+func (c *Chain) String() string {
+	return "hkdf.Chain{<redacted>}"
+}
+
+// GoString is like String, and is used by the %#v verb.
+func (c *Chain) GoString() string {
+	return "&hkdf.Chain{<redacted>}"
+}
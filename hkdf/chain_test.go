@@ -0,0 +1,78 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestChainStep(t *testing.T) {
+	initial := []byte("initial secret")
+	c := NewChain(sha256.New, initial)
+
+	key := initial
+	for i, input := range []string{"first", "second", "third"} {
+		expected := make([]byte, sha256.Size+16)
+		io.ReadFull(New(sha256.New, []byte(input), key, []byte("step")), expected)
+
+		out := c.Step([]byte(input), []byte("step"), 16)
+		if !bytes.Equal(out, expected[sha256.Size:]) {
+			t.Errorf("step %d: have %x, need %x", i, out, expected[sha256.Size:])
+		}
+		key = expected[:sha256.Size]
+	}
+
+	if !bytes.Equal(initial, []byte("initial secret")) {
+		t.Errorf("initial secret was modified")
+	}
+}
+
+func TestChainPropagation(t *testing.T) {
+	inputs := []string{"a", "b", "c", "d"}
+	run := func(changed int) [][]byte {
+		c := NewChain(sha256.New, []byte("initial secret"))
+		var outputs [][]byte
+		for i, input := range inputs {
+			if i == changed {
+				input += "'"
+			}
+			outputs = append(outputs, c.Step([]byte(input), nil, 32))
+		}
+		return outputs
+	}
+
+	base := run(-1)
+	changed := run(1)
+	for i := range inputs {
+		if same := bytes.Equal(base[i], changed[i]); same != (i < 1) {
+			t.Errorf("step %d: output equal to the unchanged chain: %v", i, same)
+		}
+	}
+}
+
+func TestChainLimit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for an output over the entropy limit")
+		}
+	}()
+	NewChain(sha256.New, nil).Step(nil, nil, 254*sha256.Size+1)
+}
+
+func TestChainNegativeLength(t *testing.T) {
+	c := NewChain(sha256.New, []byte("initial secret"))
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a negative output length")
+		}
+		if !bytes.Equal(c.key, []byte("initial secret")) {
+			t.Errorf("chain key was modified")
+		}
+	}()
+	c.Step(nil, nil, -1)
+}
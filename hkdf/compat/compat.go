@@ -0,0 +1,50 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package compat checks the output of package hkdf against the independent
+// HKDF implementation of the standard library, crypto/hkdf, to give
+// confidence when migrating from golang.org/x/crypto/hkdf or another
+// implementation. It is meant for integration tests, and is kept separate so
+// that production builds of package hkdf do not depend on it.
+package compat // import "github.com/bored-engineer/crypto/hkdf/compat"
+
+import (
+	stdhkdf "crypto/hkdf"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/bored-engineer/crypto/hkdf"
+)
+
+// CompatCheck derives n bytes of key material from the given hash, secret,
+// salt and info with package hkdf and with crypto/hkdf, and returns an error
+// if they differ, or if only one of them fails. The derived keys are not
+// included in the error.
+//
+// Note that in FIPS 140-only mode, crypto/hkdf rejects some hashes and short
+// secrets that package hkdf accepts, which CompatCheck reports as a mismatch.
+func CompatCheck(hash func() hash.Hash, secret, salt, info []byte, n int) error {
+	if n < 0 {
+		return errors.New("hkdf/compat: negative output length")
+	}
+	have, err := hkdf.DeriveKey(hash, secret, salt, info, n)
+	want, stdErr := stdhkdf.Key(hash, secret, salt, string(info), n)
+	switch {
+	case err != nil && stdErr != nil:
+		return nil
+	case err != nil || stdErr != nil:
+		return fmt.Errorf("hkdf/compat: hkdf returned error %v, crypto/hkdf returned error %v", err, stdErr)
+	}
+	defer func() {
+		for i := range have {
+			have[i], want[i] = 0, 0
+		}
+	}()
+	if subtle.ConstantTimeCompare(have, want) != 1 {
+		return fmt.Errorf("hkdf/compat: output of %d bytes differs from crypto/hkdf", n)
+	}
+	return nil
+}
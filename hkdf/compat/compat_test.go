@@ -0,0 +1,37 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package compat
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"testing"
+)
+
+func TestCompatCheck(t *testing.T) {
+	secret := []byte("input key material")
+	for _, h := range []func() hash.Hash{md5.New, sha1.New, sha256.New, sha512.New} {
+		limit := 255 * h().Size()
+		for _, n := range []int{0, 1, h().Size(), 100, limit} {
+			for _, salt := range [][]byte{nil, {}, []byte("salt")} {
+				if err := CompatCheck(h, secret, salt, []byte("info"), n); err != nil {
+					t.Errorf("%T n = %d: %v", h(), n, err)
+				}
+			}
+		}
+
+		// Both implementations reject outputs over the limit
+		if err := CompatCheck(h, secret, nil, nil, limit+1); err != nil {
+			t.Errorf("%T over the limit: %v", h(), err)
+		}
+	}
+
+	if err := CompatCheck(sha256.New, secret, nil, nil, -1); err == nil {
+		t.Errorf("expected an error for a negative length")
+	}
+}
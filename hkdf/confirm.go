@@ -0,0 +1,32 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+// confirmInfo is the info used to expand the key confirmation key.
+const confirmInfo = "confirm"
+
+// KeyConfirmation returns a tag proving knowledge of the pseudorandom key, for
+// protocols in which both parties check that they derived the same key. The
+// tag is the HMAC of transcript, keyed with the first block of output of
+// Expand for prk and the info "confirm", so that the tag reveals nothing about
+// keys expanded for other info.
+func KeyConfirmation(hash func() hash.Hash, prk []byte, transcript []byte) []byte {
+	key := make([]byte, PRKLen(hash))
+	ExpandInto(hash, prk, []byte(confirmInfo), key)
+	mac := newHMAC(hash, key)
+	mac.Write(transcript)
+	return mac.Sum(nil)
+}
+
+// VerifyKeyConfirmation reports whether tag is the KeyConfirmation tag for prk
+// and transcript, in time that does not depend on the contents of tag.
+func VerifyKeyConfirmation(hash func() hash.Hash, prk, transcript, tag []byte) bool {
+	return hmac.Equal(KeyConfirmation(hash, prk, transcript), tag)
+}
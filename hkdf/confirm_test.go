@@ -0,0 +1,44 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestKeyConfirmation(t *testing.T) {
+	prk := Extract(sha256.New, []byte("shared secret"), nil)
+	transcript := []byte("client hello, server hello")
+
+	key := make([]byte, sha256.Size)
+	io.ReadFull(Expand(sha256.New, prk, []byte("confirm")), key)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(transcript)
+	expected := mac.Sum(nil)
+
+	tag := KeyConfirmation(sha256.New, prk, transcript)
+	if !bytes.Equal(tag, expected) {
+		t.Errorf("have %x, need %x", tag, expected)
+	}
+	if !VerifyKeyConfirmation(sha256.New, prk, transcript, tag) {
+		t.Errorf("valid tag rejected")
+	}
+
+	otherPRK := Extract(sha256.New, []byte("other secret"), nil)
+	for name, tt := range map[string]struct{ prk, transcript, tag []byte }{
+		"wrong key":        {otherPRK, transcript, tag},
+		"wrong transcript": {prk, []byte("client hello"), tag},
+		"truncated tag":    {prk, transcript, tag[:16]},
+		"empty tag":        {prk, transcript, nil},
+	} {
+		if VerifyKeyConfirmation(sha256.New, tt.prk, tt.transcript, tt.tag) {
+			t.Errorf("%s: invalid tag accepted", name)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"context"
+	"hash"
+)
+
+// ExpandContext is like Expand, but the returned Reader stops generating
+// output once ctx is done. The context is checked before each block is
+// computed, and once it is done Read returns the bytes read so far together
+// with ctx.Err().
+func ExpandContext(ctx context.Context, hash func() hash.Hash, pseudorandomKey, info []byte) *Reader {
+	r := Expand(hash, pseudorandomKey, info)
+	r.ctx = ctx
+	return r
+}
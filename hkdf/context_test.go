@@ -0,0 +1,52 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestExpandContext(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("info")
+
+	expected := make([]byte, 100)
+	io.ReadFull(Expand(sha256.New, prk, info), expected)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := ExpandContext(ctx, sha256.New, prk, info)
+
+	out := make([]byte, 40)
+	if _, err := io.ReadFull(r, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(out, expected[:40]) {
+		t.Errorf("have %x, need %x", out, expected[:40])
+	}
+
+	cancel()
+
+	// The buffered remainder of the current block is still returned
+	n, err := r.Read(out)
+	if n != 24 || err != context.Canceled {
+		t.Errorf("Read after cancel: n = %d, err = %v", n, err)
+	}
+	if !bytes.Equal(out[:n], expected[40:64]) {
+		t.Errorf("have %x, need %x", out[:n], expected[40:64])
+	}
+	if n, err := r.Read(out); n != 0 || err != context.Canceled {
+		t.Errorf("second Read after cancel: n = %d, err = %v", n, err)
+	}
+	if n, err := r.WriteTo(io.Discard); n != 0 || err != context.Canceled {
+		t.Errorf("WriteTo after cancel: n = %d, err = %v", n, err)
+	}
+	if err := r.Skip(1); err != context.Canceled {
+		t.Errorf("Skip after cancel: err = %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "hash"
+
+// ExpandDynamic is like Expand, but the info of each block T(i) is returned by
+// infoFn(i), which is called right before the block is computed:
+//
+//	T(i) = HMAC-Hash(PRK, T(i-1) || infoFn(i) || i)
+//
+// This deviates from RFC 5869, which uses the same info for every block, and
+// is only meant for protocols that explicitly require it. If infoFn returns
+// the same info for every block, the output is the same as that of Expand.
+// The info passed to Reset is ignored by the returned Reader, which keeps
+// calling infoFn.
+func ExpandDynamic(hash func() hash.Hash, prk []byte, infoFn func(block byte) []byte) *Reader {
+	r := Expand(hash, prk, nil)
+	r.infoFn = infoFn
+	return r
+}
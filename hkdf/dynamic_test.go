@@ -0,0 +1,57 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestExpandDynamic(t *testing.T) {
+	for i, tt := range hkdfTests {
+		r := ExpandDynamic(tt.hash, tt.prk, func(byte) []byte { return tt.info })
+		out := make([]byte, len(tt.out))
+		if _, err := io.ReadFull(r, out); err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from ExpandDynamic: have %x, need %x", i, out, tt.out)
+		}
+	}
+
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	infoFn := func(block byte) []byte { return []byte(fmt.Sprintf("epoch %d", block)) }
+	var expected, prev []byte
+	for i := byte(1); i <= 3; i++ {
+		mac := hmac.New(sha256.New, prk)
+		mac.Write(prev)
+		mac.Write(infoFn(i))
+		mac.Write([]byte{i})
+		prev = mac.Sum(nil)
+		expected = append(expected, prev...)
+	}
+
+	r := ExpandDynamic(sha256.New, prk, infoFn)
+	out := make([]byte, len(expected))
+	if _, err := io.ReadFull(r, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(out, expected) {
+		t.Errorf("have %x, need %x", out, expected)
+	}
+
+	// Rewind and Clone keep the info function
+	r.Rewind()
+	io.ReadFull(r, out)
+	clone := make([]byte, len(expected))
+	io.ReadFull(r.Clone(), clone)
+	if !bytes.Equal(out, expected) || !bytes.Equal(clone, expected) {
+		t.Errorf("incorrect output after Rewind or Clone")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+)
+
+// FromECDH derives length bytes of key material from an elliptic curve
+// Diffie-Hellman shared secret, like DeriveKey.
+//
+// sharedX must be the x-coordinate of the shared point alone, not an encoded
+// point, in the fixed-length big-endian encoding of the field size, keeping
+// any leading zeros, as returned by the ECDH method of crypto/ecdh for the
+// NIST curves. This is the convention of TLS 1.3, JOSE and HPKE. For X25519
+// and X448, it must be the output of the function as is, which is a
+// little-endian u-coordinate.
+//
+// FromECDH returns an error if sharedX is empty or all zeros, which is the
+// output of X25519 and X448 for a low-order public key.
+func FromECDH(hash func() hash.Hash, sharedX []byte, salt, info []byte, length int) ([]byte, error) {
+	var acc byte
+	for _, b := range sharedX {
+		acc |= b
+	}
+	if acc == 0 {
+		return nil, errors.New("hkdf: empty or all-zero ECDH shared secret")
+	}
+	if length < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	return DeriveKey(hash, sharedX, salt, info, length)
+}
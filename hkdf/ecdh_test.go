@@ -0,0 +1,38 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestFromECDH(t *testing.T) {
+	for _, curve := range []ecdh.Curve{ecdh.P256(), ecdh.X25519()} {
+		alice, _ := curve.GenerateKey(rand.Reader)
+		bob, _ := curve.GenerateKey(rand.Reader)
+		sharedA, _ := alice.ECDH(bob.PublicKey())
+		sharedB, _ := bob.ECDH(alice.PublicKey())
+
+		keyA, err := FromECDH(sha256.New, sharedA, nil, []byte("info"), 32)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", curve, err)
+		}
+		keyB, _ := FromECDH(sha256.New, sharedB, nil, []byte("info"), 32)
+		expected, _ := DeriveKey(sha256.New, sharedA, nil, []byte("info"), 32)
+		if !bytes.Equal(keyA, keyB) || !bytes.Equal(keyA, expected) {
+			t.Errorf("%v: have %x and %x, need %x", curve, keyA, keyB, expected)
+		}
+	}
+
+	for _, shared := range [][]byte{nil, make([]byte, 32)} {
+		if _, err := FromECDH(sha256.New, shared, nil, nil, 32); err == nil {
+			t.Errorf("expected an error for shared secret %x", shared)
+		}
+	}
+}
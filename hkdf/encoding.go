@@ -0,0 +1,40 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+)
+
+// KeyHex is like DeriveKey, but returns the byteLen bytes of key material
+// encoded in lowercase hexadecimal, as a string of 2*byteLen characters.
+func KeyHex(hash func() hash.Hash, secret, salt, info []byte, byteLen int) (string, error) {
+	key, err := DeriveKey(hash, secret, salt, info, byteLen)
+	if err != nil {
+		return "", err
+	}
+	s := hex.EncodeToString(key)
+	for i := range key {
+		key[i] = 0
+	}
+	return s, nil
+}
+
+// KeyBase64URL is like DeriveKey, but returns the byteLen bytes of key
+// material encoded in unpadded base64url, as defined in RFC 4648, Section 5,
+// which is safe for URLs and file names.
+func KeyBase64URL(hash func() hash.Hash, secret, salt, info []byte, byteLen int) (string, error) {
+	key, err := DeriveKey(hash, secret, salt, info, byteLen)
+	if err != nil {
+		return "", err
+	}
+	s := base64.RawURLEncoding.EncodeToString(key)
+	for i := range key {
+		key[i] = 0
+	}
+	return s, nil
+}
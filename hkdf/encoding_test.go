@@ -0,0 +1,45 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func TestKeyEncoding(t *testing.T) {
+	tt := hkdfTests[0]
+	s, err := KeyHex(tt.hash, tt.master, tt.salt, tt.info, len(tt.out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if need := hex.EncodeToString(tt.out); s != need {
+		t.Errorf("KeyHex: have %s, need %s", s, need)
+	}
+
+	s, err = KeyBase64URL(tt.hash, tt.master, tt.salt, tt.info, len(tt.out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if need := base64.RawURLEncoding.EncodeToString(tt.out); s != need {
+		t.Errorf("KeyBase64URL: have %s, need %s", s, need)
+	}
+
+	limit := MaxOutputLen(sha256.New)
+	if _, err := KeyHex(sha256.New, tt.master, nil, nil, limit+1); err != ErrEntropyLimit {
+		t.Errorf("KeyHex: have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := KeyBase64URL(sha256.New, tt.master, nil, nil, limit+1); err != ErrEntropyLimit {
+		t.Errorf("KeyBase64URL: have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := KeyHex(sha256.New, tt.master, nil, nil, -1); err == nil {
+		t.Errorf("KeyHex: expected an error for a negative length")
+	}
+	if _, err := KeyBase64URL(sha256.New, tt.master, nil, nil, -1); err == nil {
+		t.Errorf("KeyBase64URL: expected an error for a negative length")
+	}
+}
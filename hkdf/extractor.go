@@ -0,0 +1,51 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "hash"
+
+// An Extractor computes the pseudorandom key of Extract over a secret that is
+// written to it in pieces, implementing io.Writer. The secret is the
+// concatenation of all data written since the Extractor was created or reset.
+type Extractor struct {
+	extractor hash.Hash
+}
+
+// NewExtractor returns an Extractor using the given hash and optional salt.
+func NewExtractor(hash func() hash.Hash, salt []byte) *Extractor {
+	if salt == nil {
+		salt = defaultSalt(hash)
+	}
+	return &Extractor{newHMAC(hash, salt)}
+}
+
+// Write adds p to the secret. It never returns an error.
+func (e *Extractor) Write(p []byte) (int, error) {
+	return e.extractor.Write(p)
+}
+
+// Sum appends the pseudorandom key for the secret written so far to b and
+// returns the resulting slice. It does not change the state of e, so more of
+// the secret can be written afterwards.
+func (e *Extractor) Sum(b []byte) []byte {
+	observeExtract()
+	return e.extractor.Sum(b)
+}
+
+// Reset discards the secret written so far, keeping the salt.
+func (e *Extractor) Reset() {
+	e.extractor.Reset()
+}
+
+// String returns a fixed string that does not reveal the secret or salt
+// written to e, so that an Extractor can be logged safely.
+func (e *Extractor) String() string {
+	return "hkdf.Extractor{<redacted>}"
+}
+
+// GoString is like String, and is used by the %#v verb.
+func (e *Extractor) GoString() string {
+	return "&hkdf.Extractor{<redacted>}"
+}
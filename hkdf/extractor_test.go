@@ -0,0 +1,32 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExtractor(t *testing.T) {
+	for i, tt := range hkdfTests {
+		e := NewExtractor(tt.hash, tt.salt)
+		half := len(tt.master) / 2
+		e.Write(tt.master[:half])
+		e.Write(tt.master[half:])
+		if prk := e.Sum(nil); !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK: have %x, need %x", i, prk, tt.prk)
+		}
+
+		// Sum does not change the state, and Reset keeps the salt
+		if prk := e.Sum([]byte("prefix")); !bytes.Equal(prk, append([]byte("prefix"), tt.prk...)) {
+			t.Errorf("test %d: incorrect PRK from a second Sum: have %x", i, prk)
+		}
+		e.Reset()
+		e.Write(tt.master)
+		if prk := e.Sum(nil); !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK after Reset: have %x, need %x", i, prk, tt.prk)
+		}
+	}
+}
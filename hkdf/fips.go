@@ -0,0 +1,107 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/fips140"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"reflect"
+)
+
+// FIPSOnly enables a policy restricting ExtractChecked, ExpandChecked and
+// NewChecked to configurations allowed by FIPS 140-3. When it is true:
+//
+//   - the hash must be one of sha256.New224, sha256.New, sha512.New384,
+//     sha512.New, sha512.New512_224 and sha512.New512_256 from the standard
+//     library, so SHA-1, MD5, other hashes, and wrapping closures are rejected;
+//   - the secret passed to Extract must be at least 112 bits long.
+//
+// The policy is also enforced whenever Go runs in FIPS 140-3 mode, as
+// reported by crypto/fips140.Enabled. In every case, the HMAC of the checked
+// variants is computed by crypto/hmac, within the Go Cryptographic Module.
+//
+// FIPSOnly does not affect the other functions of this package. It should be
+// set during initialization, as it is not safe to change concurrently with
+// its use.
+var FIPSOnly = false
+
+// fipsOnly reports whether the policy of FIPSOnly is enforced.
+func fipsOnly() bool {
+	return FIPSOnly || fips140.Enabled()
+}
+
+// fipsHashes are the hash constructors allowed when FIPSOnly is set, keyed by
+// their code pointer.
+var fipsHashes = make(map[uintptr]bool)
+
+func init() {
+	for _, h := range []func() hash.Hash{
+		sha256.New224,
+		sha256.New,
+		sha512.New384,
+		sha512.New,
+		sha512.New512_224,
+		sha512.New512_256,
+	} {
+		fipsHashes[reflect.ValueOf(h).Pointer()] = true
+	}
+}
+
+// fipsMinSecretLen is the minimum length in bytes of the secret passed to
+// Extract when FIPSOnly is set.
+const fipsMinSecretLen = 112 / 8
+
+func fipsCheckHash(hash func() hash.Hash) error {
+	if !fipsHashes[reflect.ValueOf(hash).Pointer()] {
+		return errors.New("hkdf: hash not allowed in FIPS mode")
+	}
+	return nil
+}
+
+// ExtractChecked is like Extract, but returns an error if the policy of
+// FIPSOnly is enforced and hash or secret are not allowed.
+func ExtractChecked(hash func() hash.Hash, secret, salt []byte) ([]byte, error) {
+	if fipsOnly() {
+		if err := fipsCheckHash(hash); err != nil {
+			return nil, err
+		}
+		if len(secret) < fipsMinSecretLen {
+			return nil, errors.New("hkdf: secret shorter than 112 bits not allowed in FIPS mode")
+		}
+	}
+	return Extract(hash, secret, salt), nil
+}
+
+// ExpandChecked is like Expand, but returns an error if the pseudorandom key
+// is shorter than the output of hash, as it would be if it was not returned by
+// Extract, or if the policy of FIPSOnly is enforced and hash is not allowed.
+//
+// RFC 5869 requires a pseudorandom key of at least the output size of hash,
+// and a shorter one silently weakens the output. Expand accepts it for the
+// rare protocols that need it, but new code should use ExpandChecked.
+func ExpandChecked(hash func() hash.Hash, pseudorandomKey, info []byte) (*Reader, error) {
+	if fipsOnly() {
+		if err := fipsCheckHash(hash); err != nil {
+			return nil, err
+		}
+	}
+	if len(pseudorandomKey) < PRKLen(hash) {
+		return nil, errors.New("hkdf: pseudorandom key shorter than the output of the hash")
+	}
+	return Expand(hash, pseudorandomKey, info), nil
+}
+
+// NewChecked is like New, but returns an error if the policy of FIPSOnly is
+// enforced and hash or secret are not allowed.
+func NewChecked(hash func() hash.Hash, secret, salt, info []byte) (*Reader, error) {
+	prk, err := ExtractChecked(hash, secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	return ExpandChecked(hash, prk, info)
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/fips140"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"testing"
+)
+
+func TestFIPSOnly(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x0b}, 14)
+	closure := func() hash.Hash { return sha256.New() }
+
+	// Everything is allowed by default, unless Go runs in FIPS 140-3 mode
+	for _, h := range []func() hash.Hash{md5.New, sha1.New, closure} {
+		_, err := NewChecked(h, nil, nil, nil)
+		if fips140.Enabled() && err == nil {
+			t.Errorf("%T: expected an error in FIPS 140-3 mode", h())
+		} else if !fips140.Enabled() && err != nil {
+			t.Errorf("%T: unexpected error outside of FIPS mode: %v", h(), err)
+		}
+	}
+
+	FIPSOnly = true
+	defer func() { FIPSOnly = false }()
+
+	for _, h := range []func() hash.Hash{sha256.New224, sha256.New, sha512.New384, sha512.New, sha512.New512_224, sha512.New512_256} {
+		r, err := NewChecked(h, secret, nil, []byte("info"))
+		if err != nil {
+			t.Fatalf("%T: unexpected error: %v", h(), err)
+		}
+		out, expected := make([]byte, 42), make([]byte, 42)
+		io.ReadFull(r, out)
+		io.ReadFull(New(h, secret, nil, []byte("info")), expected)
+		if !bytes.Equal(out, expected) {
+			t.Errorf("%T: incorrect output in FIPS mode", h())
+		}
+		if _, err := ExpandChecked(h, make([]byte, h().Size()), nil); err != nil {
+			t.Errorf("%T: unexpected error for a PRK of the output size: %v", h(), err)
+		}
+	}
+
+	for _, h := range []func() hash.Hash{md5.New, sha1.New, closure} {
+		if _, err := NewChecked(h, secret, nil, nil); err == nil {
+			t.Errorf("%T: expected an error from NewChecked", h())
+		}
+		if _, err := ExpandChecked(h, make([]byte, 64), nil); err == nil {
+			t.Errorf("%T: expected an error from ExpandChecked", h())
+		}
+	}
+	if _, err := ExtractChecked(sha256.New, secret[:13], nil); err == nil {
+		t.Errorf("expected an error for a secret shorter than 112 bits")
+	}
+}
+
+func TestExpandChecked(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	r, err := ExpandChecked(sha256.New, prk, []byte("info"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, expected := make([]byte, 42), make([]byte, 42)
+	io.ReadFull(r, out)
+	io.ReadFull(Expand(sha256.New, prk, []byte("info")), expected)
+	if !bytes.Equal(out, expected) {
+		t.Errorf("incorrect output: have %x, need %x", out, expected)
+	}
+
+	// A raw 16-byte secret is not a pseudorandom key for SHA-256
+	if _, err := ExpandChecked(sha256.New, prk[:16], nil); err == nil {
+		t.Errorf("expected an error for a PRK shorter than the hash output")
+	}
+	if _, err := ExpandChecked(sha256.New, nil, nil); err == nil {
+		t.Errorf("expected an error for an empty PRK")
+	}
+	if _, err := ExpandChecked(sha512.New, prk, nil); err == nil {
+		t.Errorf("expected an error for a SHA-256 PRK used with SHA-512")
+	}
+}
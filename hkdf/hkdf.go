@@ -11,44 +11,205 @@
 package hkdf // import "github.com/bored-engineer/crypto/hkdf"
 
 import (
+	"context"
 	"crypto/hmac"
+	"crypto/subtle"
 	"errors"
 	"hash"
 	"io"
 )
 
+// ErrEntropyLimit is returned when a read would exceed the maximum amount of
+// key material that can be expanded from a single pseudorandom key, which is
+// 255 times the output size of the hash.
+var ErrEntropyLimit = errors.New("hkdf: entropy limit reached")
+
+// ErrClosed is returned when reading from a Reader after Close.
+var ErrClosed = errors.New("hkdf: read from closed Reader")
+
 // Extract generates a pseudorandom key for use with Expand from an input secret
 // and an optional independent salt.
 //
+// The salt is used as an HMAC key, so a salt longer than the block size of
+// hash is hashed first, and extracts the same pseudorandom key as its hash.
+// NormalizeSalt returns the salt that is effectively used. A nil salt is
+// replaced by a string of zeros as long as the output of hash, as specified by
+// RFC 5869. An empty, non-nil salt is used as is, but since HMAC pads keys with
+// zeros to the block size, it extracts the same pseudorandom key as a nil salt.
+//
 // Only use this function if you need to reuse the extracted key with multiple
 // Expand invocations and different context values. Most common scenarios,
 // including the generation of multiple keys, should use New instead.
 func Extract(hash func() hash.Hash, secret, salt []byte) []byte {
+	return ExtractMulti(hash, salt, secret)
+}
+
+// ExtractMulti is like Extract, but the secret is the concatenation of
+// secrets, which are written to the HMAC in order without being joined first.
+//
+// Note that the order of the arguments differs from Extract.
+func ExtractMulti(hash func() hash.Hash, salt []byte, secrets ...[]byte) []byte {
+	return extractAppend(nil, hash, salt, secrets...)
+}
+
+// ExtractAppend is like Extract, but appends the pseudorandom key to dst and
+// returns the resulting slice, like the Sum method of hash.Hash. It can be used
+// to reuse a buffer across many extractions.
+func ExtractAppend(dst []byte, hash func() hash.Hash, secret, salt []byte) []byte {
+	return extractAppend(dst, hash, salt, secret)
+}
+
+// ExtractBatch is like Extract, but extracts a pseudorandom key from secret for
+// each of salts, in order. The keys share a single allocation. Since the salt
+// is the HMAC key, no HMAC state can be shared between them, but the HMAC
+// buffers are reused when hash is a constructor from the standard library.
+func ExtractBatch(hash func() hash.Hash, secret []byte, salts [][]byte) [][]byte {
+	size := PRKLen(hash)
+	buf := make([]byte, 0, len(salts)*size)
+	prks := make([][]byte, len(salts))
+	for i, salt := range salts {
+		buf = extractAppend(buf, hash, salt, secret)
+		prks[i] = buf[i*size : (i+1)*size : (i+1)*size]
+	}
+	return prks
+}
+
+func extractAppend(dst []byte, hash func() hash.Hash, salt []byte, secrets ...[]byte) []byte {
+	observeExtract()
+	if pool := macPool(hash); pool != nil {
+		// A nil salt is equivalent to an all-zero HMAC key, which the
+		// pooled HMAC pads to the block size anyway.
+		m := pool.Get().(*macState)
+		dst = m.mac(dst, salt, secrets...)
+		pool.Put(m)
+		return dst
+	}
 	if salt == nil {
-		salt = make([]byte, hash().Size())
+		salt = defaultSalt(hash)
+	}
+	extractor := newHMAC(hash, salt)
+	for _, secret := range secrets {
+		extractor.Write(secret)
+	}
+	return extractor.Sum(dst)
+}
+
+// ExtractStrict is like Extract, but returns an error if secret is empty,
+// which is almost always a mistake by the caller.
+func ExtractStrict(hash func() hash.Hash, secret, salt []byte) ([]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("hkdf: empty input keying material produces a predictable pseudorandom key")
+	}
+	return Extract(hash, secret, salt), nil
+}
+
+// ExtractSalted is like Extract, but returns an error if salt is non-empty and
+// consists only of zero bytes, which usually means that a salt buffer was
+// never filled with random data. A nil salt is still accepted and replaced by
+// zeros, as specified by RFC 5869.
+func ExtractSalted(hash func() hash.Hash, secret, salt []byte) ([]byte, error) {
+	var acc byte
+	for _, b := range salt {
+		acc |= b
+	}
+	if len(salt) > 0 && acc == 0 {
+		return nil, errors.New("hkdf: salt consists only of zero bytes")
 	}
-	extractor := hmac.New(hash, salt)
+	return Extract(hash, secret, salt), nil
+}
+
+// ExtractReader is like Extract, but reads the secret from r until EOF, so
+// that large input keying material does not have to be held in memory. It
+// returns any error encountered while reading from r.
+func ExtractReader(hash func() hash.Hash, secret io.Reader, salt []byte) ([]byte, error) {
+	extractor := NewExtractor(hash, salt)
+	if _, err := io.Copy(extractor, secret); err != nil {
+		return nil, err
+	}
+	return extractor.Sum(nil), nil
+}
+
+// ExtractMAC is like Extract, but uses the keyed MAC returned by mac in place
+// of HMAC, such as keyed BLAKE2 or KMAC. Such constructions are not defined by
+// RFC 5869.
+//
+// If salt is nil, a string of zeros as long as the output of the MAC is used,
+// which requires mac to accept a nil key.
+func ExtractMAC(mac func(key []byte) hash.Hash, secret, salt []byte) []byte {
+	observeExtract()
+	if salt == nil {
+		salt = make([]byte, mac(nil).Size())
+	}
+	extractor := mac(salt)
 	extractor.Write(secret)
 	return extractor.Sum(nil)
 }
 
-type hkdf struct {
+// PRKLen returns the length of the pseudorandom keys returned by Extract for
+// hash, which is the output size of hash.
+func PRKLen(hash func() hash.Hash) int {
+	return hash().Size()
+}
+
+// MaxOutputLen returns the maximum number of bytes that can be read from a
+// Reader using hash, which is 255 times the output size of hash.
+func MaxOutputLen(hash func() hash.Hash) int {
+	return 255 * hash().Size()
+}
+
+// EqualPRK reports whether the pseudorandom keys a and b are equal, in time
+// that does not depend on their contents. Keys of different lengths are never
+// equal.
+func EqualPRK(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// A Reader produces HKDF output keying material. It is returned by Expand
+// and New, and implements io.Reader.
+type Reader struct {
 	expander hash.Hash
 	size     int
+	ctx      context.Context
+
+	// rekey returns a new expander keyed with the pseudorandom key.
+	rekey func() hash.Hash
+
+	// audit, if not nil, is called with the index of each block generated.
+	audit func(blockIndex byte)
 
 	info    []byte
 	counter byte
 
+	// infoFn, if not nil, returns the info of each block, replacing info.
+	infoFn func(block byte) []byte
+
 	prev []byte
 	buf  []byte
+
+	// err is returned by every read once the entropy limit has been
+	// reached or f has been closed, so that the output is never restarted.
+	err error
 }
 
-func (f *hkdf) Read(p []byte) (int, error) {
+// Read fills p with the next len(p) bytes of output keying material. It
+// returns ErrEntropyLimit, and reads nothing, if fewer than len(p) bytes
+// remain. Once it has returned ErrEntropyLimit, every subsequent read fails
+// with the same error.
+//
+// A read of zero bytes always returns 0, nil, even at the entropy limit,
+// unless f has been closed.
+func (f *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 && f.err != ErrClosed {
+		return 0, nil
+	}
+	if f.err != nil {
+		return 0, f.err
+	}
 	// Check whether enough data can be generated
 	need := len(p)
-	remains := len(f.buf) + int(255-f.counter+1)*f.size
-	if remains < need {
-		return 0, errors.New("hkdf: entropy limit reached")
+	if f.Remaining() < need {
+		f.err = ErrEntropyLimit
+		return 0, f.err
 	}
 	// Read any leftover from the buffer
 	n := copy(p, f.buf)
@@ -56,38 +217,575 @@ func (f *hkdf) Read(p []byte) (int, error) {
 
 	// Fill the rest of the buffer
 	for len(p) > 0 {
-		f.expander.Reset()
-		f.expander.Write(f.prev)
-		f.expander.Write(f.info)
-		f.expander.Write([]byte{f.counter})
-		f.prev = f.expander.Sum(f.prev[:0])
-		f.counter++
+		if err := f.canceled(); err != nil {
+			f.buf = nil
+			observeExpand(need - len(p))
+			return need - len(p), err
+		}
+		f.next()
 
 		// Copy the new batch into p
-		f.buf = f.prev
 		n = copy(p, f.buf)
 		p = p[n:]
 	}
 	// Save leftovers for next run
 	f.buf = f.buf[n:]
 
+	observeExpand(need)
 	return need, nil
 }
 
+// WriteTo writes output keying material to w until the entropy limit is
+// reached, implementing io.WriterTo. It consumes the full remaining entropy
+// budget of f, and on success returns the number of bytes written together
+// with ErrEntropyLimit.
+func (f *Reader) WriteTo(w io.Writer) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	var written int64
+	for f.Remaining() > 0 {
+		if len(f.buf) == 0 {
+			if err := f.canceled(); err != nil {
+				return written, err
+			}
+			f.next()
+		}
+		n, err := w.Write(f.buf)
+		written += int64(n)
+		observeExpand(n)
+		f.buf = f.buf[n:]
+		if err != nil {
+			return written, err
+		}
+	}
+	f.err = ErrEntropyLimit
+	return written, f.err
+}
+
+// ReadInto fills dst with the next len(dst) bytes of output keying material,
+// like Read. Unlike the general contract of io.Reader, it never returns fewer
+// than len(dst) bytes without an error: either dst is filled and the error is
+// nil, or ErrEntropyLimit or ErrClosed is returned and nothing is read, or the
+// context of f is canceled and its error is returned with the bytes read so
+// far. It can therefore be used without io.ReadFull.
+//
+// ReadInto does not allocate when the hash is a constructor of the standard
+// library.
+func (f *Reader) ReadInto(dst []byte) (int, error) {
+	return f.Read(dst)
+}
+
+// Skip discards the next n bytes of output keying material, as if they had
+// been read. The skipped blocks are still computed, since each block depends
+// on the previous one. It returns ErrEntropyLimit, and skips nothing, if fewer
+// than n bytes remain.
+//
+// If the context of f is canceled, Skip returns its error, and the bytes
+// skipped so far stay consumed.
+func (f *Reader) Skip(n int) error {
+	if f.err != nil {
+		return f.err
+	}
+	if n < 0 {
+		return errors.New("hkdf: negative skip length")
+	}
+	if f.Remaining() < n {
+		f.err = ErrEntropyLimit
+		return f.err
+	}
+	for n > len(f.buf) {
+		if err := f.canceled(); err != nil {
+			f.buf = nil
+			return err
+		}
+		n -= len(f.buf)
+		f.next()
+	}
+	f.buf = f.buf[n:]
+	return nil
+}
+
+// NextBlock returns a copy of the next block T(i) of output keying material,
+// as defined in RFC 5869, Section 2.3, together with its index i. Any
+// unread bytes of the current block are discarded, so that the output stays
+// aligned to blocks. It returns ErrEntropyLimit once all 255 blocks have been
+// produced.
+func (f *Reader) NextBlock() ([]byte, byte, error) {
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	if f.counter == 0 {
+		f.err = ErrEntropyLimit
+		return nil, 0, f.err
+	}
+	if err := f.canceled(); err != nil {
+		return nil, 0, err
+	}
+	f.next()
+	f.buf = nil
+	return append([]byte(nil), f.prev...), f.counter - 1, nil
+}
+
+// Reset restarts f with new context info, as if it had been returned by
+// Expand with the same pseudorandom key, clearing any ErrEntropyLimit error.
+// The keyed HMAC is reused, avoiding the cost of setting it up again. Reset
+// has no effect on a closed Reader, and must not be called concurrently with
+// Read.
+func (f *Reader) Reset(info []byte) {
+	if f.err == ErrClosed {
+		return
+	}
+	f.err = nil
+	f.info = info
+	f.counter = 1
+	f.prev = f.prev[:0]
+	f.buf = nil
+}
+
+// Rewind restarts f at the beginning of its output, like Reset with the same
+// info, so that the same output keying material is produced again. It can be
+// called at any point, including in the middle of a block.
+func (f *Reader) Rewind() {
+	f.Reset(f.info)
+}
+
+// Close overwrites the buffered output keying material of f with zeros and
+// releases its keyed HMAC, after which Read returns ErrClosed. The HMAC state
+// derived from the pseudorandom key cannot be wiped, and the info passed to
+// Expand is left untouched, as it is owned by the caller. Close always
+// returns nil.
+func (f *Reader) Close() error {
+	prev := f.prev[:cap(f.prev)]
+	for i := range prev {
+		prev[i] = 0
+	}
+	f.err = ErrClosed
+	f.expander = nil
+	f.rekey = nil
+	f.audit = nil
+	f.info = nil
+	f.infoFn = nil
+	f.prev = nil
+	f.buf = nil
+	return nil
+}
+
+// Clone returns a new Reader for the same pseudorandom key, info, context and
+// audit hook as f, positioned at the beginning of the output. The clone shares no
+// mutable state with f. If the keyed HMAC of f can be cloned, as the HMACs of
+// crypto/hmac can since Go 1.25, it is cloned, avoiding the cost of keying a
+// new one.
+func (f *Reader) Clone() *Reader {
+	c := &Reader{size: f.size, ctx: f.ctx, rekey: f.rekey, audit: f.audit, info: f.info, infoFn: f.infoFn, counter: 1, prev: make([]byte, 0, f.size)}
+	if f.err == ErrClosed {
+		c.err = ErrClosed
+		return c
+	}
+	if h, ok := f.expander.(cloner); ok {
+		if clone, err := h.Clone(); err == nil {
+			c.expander = clone
+			return c
+		}
+	}
+	c.expander = f.rekey()
+	return c
+}
+
+// cloner is implemented by hashes that can be duplicated in their current
+// state. It matches hash.Cloner, which requires Go 1.25.
+type cloner interface {
+	Clone() (hash.Hash, error)
+}
+
+// String returns a fixed string that does not reveal the output keying
+// material or any key held by f, so that a Reader can be logged safely.
+func (f *Reader) String() string {
+	return "hkdf.Reader{<redacted>}"
+}
+
+// GoString is like String, and is used by the %#v verb.
+func (f *Reader) GoString() string {
+	return "&hkdf.Reader{<redacted>}"
+}
+
+// canceled returns the error of the context of f, if any.
+func (f *Reader) canceled() error {
+	if f.ctx == nil {
+		return nil
+	}
+	return f.ctx.Err()
+}
+
+// next generates the next block of output into f.prev and makes it the
+// current buffer.
+func (f *Reader) next() {
+	if f.counter == 0 {
+		// The counter wrapped around, and the output would repeat.
+		panic("hkdf: counter overflow")
+	}
+	if f.infoFn != nil {
+		f.info = f.infoFn(f.counter)
+	}
+	f.expander.Reset()
+	f.expander.Write(f.prev)
+	f.expander.Write(f.info)
+	f.expander.Write(counters[f.counter:][:1])
+	f.prev = f.expander.Sum(f.prev[:0])
+	f.counter++
+	f.buf = f.prev
+	if f.audit != nil {
+		f.audit(f.counter - 1)
+	}
+}
+
+// Remaining returns the number of bytes that can still be read from f before
+// the entropy limit is reached.
+func (f *Reader) Remaining() int {
+	if f.err != nil {
+		return 0
+	}
+	// Blocks counter through 255 are still to be generated. A counter of 0
+	// means that it wrapped around after block 255, and none are left.
+	blocks := 0
+	if f.counter != 0 {
+		blocks = 256 - int(f.counter)
+	}
+	return len(f.buf) + blocks*f.size
+}
+
 // Expand returns a Reader, from which keys can be read, using the given
 // pseudorandom key and optional context info, skipping the extraction step.
 //
 // The pseudorandomKey should have been generated by Extract, or be a uniformly
 // random or pseudorandom cryptographically strong key. See RFC 5869, Section
-// 3.3. Most common scenarios will want to use New instead.
-func Expand(hash func() hash.Hash, pseudorandomKey, info []byte) io.Reader {
-	expander := hmac.New(hash, pseudorandomKey)
-	return &hkdf{expander, expander.Size(), info, 1, nil, nil}
+// 3.3. Most common scenarios will want to use New instead. ExpandChecked
+// rejects pseudorandom keys shorter than the output of hash.
+func Expand(hash func() hash.Hash, pseudorandomKey, info []byte) *Reader {
+	return newReader(keyedHMAC(hash, pseudorandomKey), info)
+}
+
+// ExpandInto expands len(out) bytes of output keying material from the
+// pseudorandom key and optional context info directly into out, using a single
+// HMAC and no Reader. It returns ErrEntropyLimit, and writes nothing, if
+// len(out) is longer than the limit for hash.
+func ExpandInto(hash func() hash.Hash, pseudorandomKey, info, out []byte) error {
+	expander := newHMAC(hash, pseudorandomKey)
+	size := expander.Size()
+	if len(out) > 255*size {
+		return ErrEntropyLimit
+	}
+	defer observeExpand(len(out))
+
+	var prev []byte
+	var counter [1]byte
+	for counter[0] = 1; len(out) > 0; counter[0]++ {
+		expander.Reset()
+		expander.Write(prev)
+		expander.Write(info)
+		expander.Write(counter[:])
+		if len(out) >= size {
+			// Sum appends to the empty prefix of out, in place.
+			prev = expander.Sum(out[:0])
+			out = out[size:]
+		} else {
+			// prev is part of the output, so the last partial block
+			// needs a buffer of its own.
+			copy(out, expander.Sum(nil))
+			out = nil
+		}
+	}
+	return nil
 }
 
+// firstCounter is the counter byte of the first block of output.
+var firstCounter = []byte{0x01}
+
+// ExpandBlock expands the first len(out) bytes of output keying material from
+// the pseudorandom key and optional context info into out, which must not be
+// longer than the output of hash. It computes the single block
+// T(1) = HMAC(prk, info || 0x01) directly, and does not allocate for the hash
+// constructors of the standard library, unless the policy of FIPSOnly is
+// enforced.
+func ExpandBlock(hash func() hash.Hash, prk, info []byte, out []byte) error {
+	pool := macPool(hash)
+	if pool == nil {
+		if len(out) > hash().Size() {
+			return errors.New("hkdf: output longer than one block")
+		}
+		return ExpandInto(hash, prk, info, out)
+	}
+
+	m := pool.Get().(*macState)
+	defer pool.Put(m)
+	switch size := m.inner.Size(); {
+	case len(out) > size:
+		return errors.New("hkdf: output longer than one block")
+	case len(out) == size:
+		m.mac(out[:0], prk, info, firstCounter)
+	default:
+		m.block = m.mac(m.block[:0], prk, info, firstCounter)
+		copy(out, m.block)
+		for i := range m.block {
+			m.block[i] = 0
+		}
+	}
+	observeExpand(len(out))
+	return nil
+}
+
+// ExpandMAC is like Expand, but uses the MAC returned by mac keyed with the
+// pseudorandom key in place of HMAC. Such constructions are not defined by
+// RFC 5869, but mac can also return an HMAC computed elsewhere, such as by a
+// hardware security module, which is equivalent to Expand.
+//
+// The mac function is called once, when the Reader is created, and again only
+// by Clone. For each block T(i) the Reader calls, in order, Reset, Write with
+// T(i-1) (empty for the first block), Write with info, Write with the single
+// byte i, and Sum. The MAC must not change its state in Sum, and Reset must
+// restore it to its keyed initial state, like the HMACs of crypto/hmac.
+func ExpandMAC(mac func(key []byte) hash.Hash, pseudorandomKey, info []byte) *Reader {
+	return newReader(func() hash.Hash { return mac(pseudorandomKey) }, info)
+}
+
+// keyedHMAC returns a function returning new HMACs using h and key.
+func keyedHMAC(h func() hash.Hash, key []byte) func() hash.Hash {
+	return func() hash.Hash { return newHMAC(h, key) }
+}
+
+// CheckHash returns an error if hash cannot be used with this package, that is
+// if it is nil, returns a nil hash.Hash, or returns a hash.Hash without a
+// positive output size. The other functions of this package panic when given
+// such a hash constructor.
+func CheckHash(hash func() hash.Hash) error {
+	if hash == nil {
+		return errors.New("hkdf: hash constructor is nil")
+	}
+	h := hash()
+	if h == nil {
+		return errors.New("hkdf: hash constructor returned nil")
+	}
+	if h.Size() <= 0 {
+		return errors.New(errNoFixedSize)
+	}
+	return nil
+}
+
+// errNoFixedSize is the panic message for hashes with no fixed output size,
+// such as SHAKE wrapped in a hash.Hash, whose Size is usually 0.
+const errNoFixedSize = "hkdf: hash has no fixed output size; HKDF requires a fixed-length hash"
+
+// newHMAC is like hmac.New, but panics with a descriptive message if h is
+// rejected by CheckHash.
+func newHMAC(h func() hash.Hash, key []byte) hash.Hash {
+	defer checkHashOnPanic(h)
+	m := hmac.New(h, key)
+	if m.Size() <= 0 {
+		panic(errNoFixedSize)
+	}
+	return m
+}
+
+// defaultSalt returns the salt used when none is provided, a string of zeros
+// as long as the output of h.
+func defaultSalt(h func() hash.Hash) []byte {
+	defer checkHashOnPanic(h)
+	size := h().Size()
+	if size <= 0 {
+		panic(errNoFixedSize)
+	}
+	return make([]byte, size)
+}
+
+// checkHashOnPanic replaces a panic with the error from CheckHash, if any, so
+// that invalid hash constructors are reported clearly. It must be deferred.
+func checkHashOnPanic(h func() hash.Hash) {
+	if r := recover(); r != nil {
+		if err := CheckHash(h); err != nil {
+			panic(err.Error())
+		}
+		panic(r)
+	}
+}
+
+func newReader(rekey func() hash.Hash, info []byte) *Reader {
+	expander := rekey()
+	if expander.Size() <= 0 {
+		panic(errNoFixedSize)
+	}
+	size := expander.Size()
+	return &Reader{expander: expander, size: size, rekey: rekey, info: info, counter: 1, prev: make([]byte, 0, size)}
+}
+
+// counters holds every value of the block counter, so that writing one to
+// the expander does not allocate.
+var counters = func() (c [256]byte) {
+	for i := range c {
+		c[i] = byte(i)
+	}
+	return c
+}()
+
 // New returns a Reader, from which keys can be read, using the given hash,
 // secret, salt and context info. Salt and info can be nil.
-func New(hash func() hash.Hash, secret, salt, info []byte) io.Reader {
+func New(hash func() hash.Hash, secret, salt, info []byte) *Reader {
 	prk := Extract(hash, secret, salt)
 	return Expand(hash, prk, info)
 }
+
+// NewN is like New, but first checks that totalLen bytes, the amount of key
+// material the caller intends to read, can be derived with hash. It returns
+// ErrEntropyLimit, before doing any derivation work, if totalLen exceeds the
+// limit. The returned Reader is not limited to totalLen bytes.
+func NewN(hash func() hash.Hash, secret, salt, info []byte, totalLen int) (*Reader, error) {
+	if totalLen < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	if totalLen > MaxOutputLen(hash) {
+		return nil, ErrEntropyLimit
+	}
+	return New(hash, secret, salt, info), nil
+}
+
+// NewSplit is like New, but uses extractHash for Extract and expandHash for
+// Expand. Using different hashes is not defined by RFC 5869, and is only meant
+// for experiments and compatibility with systems doing so. If both hashes are
+// the same, the output is the same as that of New.
+func NewSplit(extractHash, expandHash func() hash.Hash, secret, salt, info []byte) *Reader {
+	prk := Extract(extractHash, secret, salt)
+	return Expand(expandHash, prk, info)
+}
+
+// NewWithPRK is like New, but also returns the pseudorandom key extracted from
+// secret and salt. The key can be passed to Expand later to derive keys for
+// other contexts, or used to compute a key confirmation value, without running
+// Extract again.
+//
+// The pseudorandom key is the actual secret from which all output is derived,
+// and is as sensitive as secret itself. It should be overwritten with zeros
+// once it is no longer needed.
+func NewWithPRK(hash func() hash.Hash, secret, salt, info []byte) (reader *Reader, prk []byte) {
+	prk = Extract(hash, secret, salt)
+	return Expand(hash, prk, info), prk
+}
+
+// Key derives len(out) bytes of key material from the given hash, secret, salt
+// and context info, and writes them to out. Salt and info can be nil.
+//
+// It returns ErrEntropyLimit if len(out) exceeds the amount of key material
+// that can be derived with hash.
+func Key(hash func() hash.Hash, secret, salt, info, out []byte) error {
+	_, err := io.ReadFull(New(hash, secret, salt, info), out)
+	return err
+}
+
+// DeriveKey is like Key, but returns a newly allocated slice of length bytes.
+func DeriveKey(hash func() hash.Hash, secret, salt, info []byte, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	out := make([]byte, length)
+	if err := Key(hash, secret, salt, info, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OneKey derives a single key as long as the output of hash from the given
+// secret, salt and context info. It returns the same key as reading that many
+// bytes from New, but when hash is a constructor of the standard library, the
+// Extract and Expand HMACs share the same pooled hash states, and the returned
+// key is the only allocation, unless the policy of FIPSOnly is enforced.
+//
+// OneKey returns an error if hash cannot be used with this package.
+func OneKey(hash func() hash.Hash, secret, salt, info []byte) ([]byte, error) {
+	pool := macPool(hash)
+	if pool == nil {
+		if err := CheckHash(hash); err != nil {
+			return nil, err
+		}
+		return DeriveKey(hash, secret, salt, info, hash().Size())
+	}
+
+	m := pool.Get().(*macState)
+	defer pool.Put(m)
+	observeExtract()
+	prk := m.mac(m.block[:0], salt, secret)
+	key := m.mac(make([]byte, 0, len(prk)), prk, info, firstCounter)
+	for i := range prk {
+		prk[i] = 0
+	}
+	observeExpand(len(key))
+	return key, nil
+}
+
+// A KDF derives keys of arbitrary length from context info, so that code can
+// be written against HKDF and other key derivation functions alike.
+type KDF interface {
+	// DeriveKey returns length bytes of key material bound to info.
+	DeriveKey(info []byte, length int) ([]byte, error)
+}
+
+type kdf struct {
+	hash func() hash.Hash
+	prk  []byte
+}
+
+// NewKDF returns a KDF that extracts a pseudorandom key from secret and salt
+// once, and expands it with each info passed to DeriveKey. Salt can be nil.
+// The returned KDF is safe for concurrent use.
+func NewKDF(hash func() hash.Hash, secret, salt []byte) KDF {
+	return &kdf{hash, Extract(hash, secret, salt)}
+}
+
+func (k *kdf) DeriveKey(info []byte, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	out := make([]byte, length)
+	if _, err := io.ReadFull(Expand(k.hash, k.prk, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (k *kdf) String() string {
+	return "hkdf.KDF{<redacted>}"
+}
+
+func (k *kdf) GoString() string {
+	return "&hkdf.KDF{<redacted>}"
+}
+
+// A KeyRequest describes a key to derive with ExpandMany.
+type KeyRequest struct {
+	Info   []byte // context info the key is bound to
+	Length int    // length of the key in bytes
+}
+
+// ExpandMany derives one key for each of requests from the pseudorandom key
+// prk. Each key is expanded with its own info, so keys are domain separated
+// rather than consecutive slices of a single output stream. It returns
+// ErrEntropyLimit if any request is longer than the limit for hash, and an
+// error if any request has a negative length.
+func ExpandMany(hash func() hash.Hash, prk []byte, requests []KeyRequest) ([][]byte, error) {
+	for _, req := range requests {
+		if req.Length < 0 {
+			return nil, errors.New("hkdf: negative output length")
+		}
+	}
+	var r *Reader
+	keys := make([][]byte, len(requests))
+	for i, req := range requests {
+		if r == nil {
+			r = Expand(hash, prk, req.Info)
+		} else {
+			r.Reset(req.Info)
+		}
+		keys[i] = make([]byte, req.Length)
+		if _, err := io.ReadFull(r, keys[i]); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
@@ -5,15 +5,40 @@ package hkdf
 
 import (
 	"bytes"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
 	"hash"
 	"io"
+	"strings"
 	"testing"
+
+	"github.com/bored-engineer/crypto/blake2b"
+	"github.com/bored-engineer/crypto/sha3"
+)
+
+// New and Expand return a *Reader, which can still be used as an io.Reader.
+var (
+	_ *Reader   = New(sha256.New, nil, nil, nil)
+	_ *Reader   = Expand(sha256.New, make([]byte, sha256.Size), nil)
+	_ io.Reader = New(sha256.New, nil, nil, nil)
+
+	_ io.WriterTo = (*Reader)(nil)
+	_ io.Closer   = (*Reader)(nil)
 )
 
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
 type hkdfTest struct {
 	hash   func() hash.Hash
 	master []byte
@@ -344,6 +369,89 @@ func TestHKDFMultiRead(t *testing.T) {
 	}
 }
 
+func TestHKDFChunkedReads(t *testing.T) {
+	for _, hash := range []func() hash.Hash{md5.New, sha1.New, sha256.New, sha512.New} {
+		size := hash().Size()
+		prk := Extract(hash, []byte("secret"), nil)
+		expected := make([]byte, 255*size)
+		io.ReadFull(Expand(hash, prk, []byte("info")), expected)
+
+		patterns := map[string][]int{
+			"bytes":     {1},
+			"primes":    {2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71},
+			"blocks":    {size},
+			"block+1":   {size + 1},
+			"block-1":   {size - 1},
+			"two+1":     {2*size + 1},
+			"mixed":     {1, size, size - 1, 2, size + 1, 3 * size},
+			"some zero": {0, 1, 0, size, 0},
+		}
+		for name, sizes := range patterns {
+			// Each read must see its bytes before the next Sum reuses
+			// the storage of the previous block
+			r := Expand(hash, prk, []byte("info"))
+			out := make([]byte, 0, len(expected))
+			for i := 0; len(out) < len(expected); i++ {
+				n := sizes[i%len(sizes)]
+				if n > len(expected)-len(out) {
+					n = len(expected) - len(out)
+				}
+				chunk := make([]byte, n)
+				if _, err := io.ReadFull(r, chunk); err != nil {
+					t.Fatalf("%T: %s: unexpected error after %d bytes: %v", hash(), name, len(out), err)
+				}
+				out = append(out, chunk...)
+			}
+			if !bytes.Equal(out, expected) {
+				t.Errorf("%T: %s: output differs from a single read", hash(), name)
+			}
+		}
+
+		// Interleave Read, Skip and WriteTo
+		r := Expand(hash, prk, []byte("info"))
+		first, second := make([]byte, size-1), make([]byte, size+3)
+		io.ReadFull(r, first)
+		r.Skip(2)
+		io.ReadFull(r, second)
+		var rest bytes.Buffer
+		r.WriteTo(&rest)
+		if !bytes.Equal(first, expected[:size-1]) ||
+			!bytes.Equal(second, expected[size+1:2*size+4]) ||
+			!bytes.Equal(rest.Bytes(), expected[2*size+4:]) {
+			t.Errorf("%T: interleaved Read, Skip and WriteTo produced incorrect output", hash())
+		}
+	}
+}
+
+func TestReadInto(t *testing.T) {
+	for _, size := range []int{0, 1, 7, 31, 32, 33, 64, 100} {
+		r := New(sha256.New, []byte("secret"), nil, nil)
+		dst := make([]byte, size)
+		for {
+			n, err := r.ReadInto(dst)
+			if err != nil {
+				if n != 0 || err != ErrEntropyLimit {
+					t.Errorf("size %d: n = %d, err = %v", size, n, err)
+				}
+				break
+			}
+			if n != size {
+				t.Fatalf("size %d: short read of %d bytes without an error", size, n)
+			}
+			if size == 0 {
+				break
+			}
+		}
+	}
+
+	// Allocations only happen when creating the Reader
+	r := New(sha256.New, []byte("secret"), nil, nil)
+	dst := make([]byte, 45)
+	if allocs := testing.AllocsPerRun(100, func() { r.ReadInto(dst) }); allocs != 0 {
+		t.Errorf("ReadInto allocates %v times, need 0", allocs)
+	}
+}
+
 func TestHKDFLimit(t *testing.T) {
 	hash := sha1.New
 	master := []byte{0x00, 0x01, 0x02, 0x03}
@@ -361,11 +469,129 @@ func TestHKDFLimit(t *testing.T) {
 
 	// Reading one more should fail
 	n, err = io.ReadFull(hkdf, make([]byte, 1))
-	if n > 0 || err == nil {
+	if n > 0 || err != ErrEntropyLimit {
 		t.Errorf("key expansion overflowed: n = %d, err = %v", n, err)
 	}
 }
 
+func TestHKDFLimitPartial(t *testing.T) {
+	hash := sha1.New
+	master := []byte{0x00, 0x01, 0x02, 0x03}
+
+	hkdf := New(hash, master, nil, nil)
+	limit := hash().Size() * 255
+
+	if _, err := io.ReadFull(hkdf, make([]byte, limit-1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A read crossing the limit should not return any bytes
+	n, err := hkdf.Read(make([]byte, 2))
+	if n != 0 || err != ErrEntropyLimit {
+		t.Errorf("partial read across limit: n = %d, err = %v", n, err)
+	}
+}
+
+func TestHKDFLimitBoundary(t *testing.T) {
+	for _, hash := range []func() hash.Hash{md5.New, sha1.New, sha256.New, sha512.New} {
+		size := hash().Size()
+		limit := 255 * size
+		prk := Extract(hash, []byte("secret"), nil)
+		expected := make([]byte, limit)
+		if err := ExpandInto(hash, prk, nil, expected); err != nil {
+			t.Fatalf("%T: unexpected error: %v", hash(), err)
+		}
+
+		// Exactly the limit succeeds in a single read, one more byte fails
+		out := make([]byte, limit+1)
+		if n, err := Expand(hash, prk, nil).Read(out); n != 0 || err != ErrEntropyLimit {
+			t.Errorf("%T: read of %d bytes: n = %d, err = %v", hash(), limit+1, n, err)
+		}
+		if n, err := Expand(hash, prk, nil).Read(out[:limit]); n != limit || err != nil {
+			t.Errorf("%T: read of %d bytes: n = %d, err = %v", hash(), limit, n, err)
+		}
+
+		// With the counter at 255 and an empty buffer, the last block is
+		// still produced, and is not a restart of the output
+		r := Expand(hash, prk, nil)
+		io.ReadFull(r, out[:254*size])
+		if r.counter != 255 || len(r.buf) != 0 || r.Remaining() != size {
+			t.Fatalf("%T: counter %d, %d buffered, %d remaining", hash(), r.counter, len(r.buf), r.Remaining())
+		}
+		last := make([]byte, size)
+		if n, err := r.Read(last); n != size || err != nil {
+			t.Errorf("%T: reading the last block: n = %d, err = %v", hash(), n, err)
+		}
+		if !bytes.Equal(last, expected[254*size:]) {
+			t.Errorf("%T: incorrect last block: have %x, need %x", hash(), last, expected[254*size:])
+		}
+		if r.counter != 0 || r.Remaining() != 0 {
+			t.Errorf("%T: counter %d, %d remaining after the last block", hash(), r.counter, r.Remaining())
+		}
+		if n, err := r.Read(out[:1]); n != 0 || err != ErrEntropyLimit {
+			t.Errorf("%T: read past the limit: n = %d, err = %v", hash(), n, err)
+		}
+	}
+}
+
+func TestNilAndEmpty(t *testing.T) {
+	secret := []byte("secret")
+	closure := func() hash.Hash { return sha256.New() }
+	for _, h := range []func() hash.Hash{sha256.New, sha512.New, closure} {
+		// A nil and an empty salt are both padded to zeros by HMAC
+		prk := Extract(h, secret, nil)
+		if empty := Extract(h, secret, []byte{}); !bytes.Equal(empty, prk) {
+			t.Errorf("%T: empty salt: have %x, need %x", h(), empty, prk)
+		}
+
+		// A nil and an empty info both write nothing to the HMAC
+		expected := make([]byte, 100)
+		io.ReadFull(Expand(h, prk, nil), expected)
+		out := make([]byte, len(expected))
+		io.ReadFull(Expand(h, prk, []byte{}), out)
+		if !bytes.Equal(out, expected) {
+			t.Errorf("%T: empty info: have %x, need %x", h(), out, expected)
+		}
+		io.ReadFull(New(h, secret, []byte{}, []byte{}), out)
+		if !bytes.Equal(out, expected) {
+			t.Errorf("%T: empty salt and info: have %x, need %x", h(), out, expected)
+		}
+	}
+}
+
+func TestHKDFEmptyRead(t *testing.T) {
+	hkdf := New(sha256.New, []byte("secret"), nil, nil)
+	limit := 255 * sha256.Size
+	check := func(state string, err error) {
+		t.Helper()
+		if n, rerr := hkdf.Read(nil); n != 0 || rerr != err {
+			t.Errorf("empty read %s: n = %d, err = %v, need %v", state, n, rerr, err)
+		}
+		if n, rerr := hkdf.Read([]byte{}); n != 0 || rerr != err {
+			t.Errorf("empty read %s: n = %d, err = %v, need %v", state, n, rerr, err)
+		}
+	}
+
+	check("at the start", nil)
+	io.ReadFull(hkdf, make([]byte, 45))
+	check("mid-block", nil)
+	io.ReadFull(hkdf, make([]byte, limit-45))
+	check("with the budget exhausted", nil)
+
+	// Reads after the limit keep returning the sticky error, but empty
+	// reads do not
+	if _, err := hkdf.Read(make([]byte, 1)); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	check("after ErrEntropyLimit", nil)
+	if _, err := hkdf.Read(make([]byte, 1)); err != ErrEntropyLimit {
+		t.Errorf("have %v after an empty read, need %v", err, ErrEntropyLimit)
+	}
+
+	hkdf.Close()
+	check("after Close", ErrClosed)
+}
+
 func Benchmark16ByteMD5Single(b *testing.B) {
 	benchmarkHKDFSingle(md5.New, 16, b)
 }
@@ -447,3 +673,848 @@ func benchmarkHKDFStream(hasher func() hash.Hash, block int, b *testing.B) {
 		}
 	}
 }
+
+// opaqueHash hides the encoding.BinaryMarshaler implementation of a hash, so
+// that crypto/hmac cannot cache its keyed state.
+type opaqueHash struct{ hash.Hash }
+
+func newOpaqueSHA256() hash.Hash { return opaqueHash{sha256.New()} }
+
+func TestExpandCachedState(t *testing.T) {
+	// The expander is reset before every block, which restores the keyed
+	// state from a snapshot when the hash can be marshaled. The output must
+	// be the same as when the key is processed again.
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	expected := make([]byte, MaxOutputLen(sha256.New))
+	io.ReadFull(Expand(newOpaqueSHA256, prk, []byte("info")), expected)
+	out := make([]byte, len(expected))
+	io.ReadFull(Expand(sha256.New, prk, []byte("info")), out)
+	if !bytes.Equal(out, expected) {
+		t.Errorf("output with a cached keyed state differs")
+	}
+}
+
+func BenchmarkExpandLargeSHA256(b *testing.B) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("keystream")
+	out := make([]byte, MaxOutputLen(sha256.New))
+
+	for _, bb := range []struct {
+		name string
+		hash func() hash.Hash
+	}{
+		{"Cached", sha256.New},
+		{"Uncached", newOpaqueSHA256},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(int64(len(out)))
+			for i := 0; i < b.N; i++ {
+				io.ReadFull(Expand(bb.hash, prk, info), out)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	for i, tt := range hkdfTests {
+		out := make([]byte, len(tt.out))
+		if err := Key(tt.hash, tt.master, tt.salt, tt.info, out); err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from Key: have %v, need %v.", i, out, tt.out)
+		}
+
+		out, err := DeriveKey(tt.hash, tt.master, tt.salt, tt.info, len(tt.out))
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from DeriveKey: have %v, need %v.", i, out, tt.out)
+		}
+	}
+
+	limit := sha256.Size * 255
+	if err := Key(sha256.New, []byte("secret"), nil, nil, make([]byte, limit+1)); err != ErrEntropyLimit {
+		t.Errorf("Key over the limit: have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := DeriveKey(sha256.New, []byte("secret"), nil, nil, limit+1); err != ErrEntropyLimit {
+		t.Errorf("DeriveKey over the limit: have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := DeriveKey(sha256.New, []byte("secret"), nil, nil, -1); err == nil {
+		t.Errorf("expected an error from DeriveKey for a negative length")
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	hkdf := New(sha256.New, []byte("secret"), nil, nil)
+	limit := sha256.Size * 255
+
+	for _, n := range []int{0, 1, 31, 32, 33, 100, limit - 197} {
+		before := hkdf.Remaining()
+		if _, err := io.ReadFull(hkdf, make([]byte, n)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if have, need := hkdf.Remaining(), before-n; have != need {
+			t.Errorf("after reading %d bytes: have %d remaining, need %d", n, have, need)
+		}
+	}
+	if r := hkdf.Remaining(); r != 0 {
+		t.Errorf("have %d remaining at the limit, need 0", r)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	hash := sha1.New
+	master := []byte{0x00, 0x01, 0x02, 0x03}
+	limit := hash().Size() * 255
+
+	expected := make([]byte, limit)
+	if _, err := io.ReadFull(New(hash, master, nil, nil), expected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Start mid-block to exercise the leftover buffer
+	hkdf := New(hash, master, nil, nil)
+	head := make([]byte, 7)
+	if _, err := io.ReadFull(hkdf, head); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, hkdf)
+	if err != ErrEntropyLimit {
+		t.Errorf("have error %v, need %v", err, ErrEntropyLimit)
+	}
+	if n != int64(limit-len(head)) {
+		t.Errorf("have %d bytes written, need %d", n, limit-len(head))
+	}
+	if !bytes.Equal(append(head, buf.Bytes()...), expected) {
+		t.Errorf("WriteTo output does not match Read output")
+	}
+	if hkdf.Remaining() != 0 {
+		t.Errorf("have %d bytes remaining after WriteTo, need 0", hkdf.Remaining())
+	}
+}
+
+func TestReset(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	infos := [][]byte{nil, []byte("first"), []byte("second")}
+
+	hkdf := Expand(sha256.New, prk, []byte("initial"))
+	for i, info := range infos {
+		// Leave the reader mid-block before resetting it
+		if _, err := io.ReadFull(hkdf, make([]byte, 45)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		hkdf.Reset(info)
+
+		expected := make([]byte, 100)
+		io.ReadFull(Expand(sha256.New, prk, info), expected)
+
+		out := make([]byte, len(expected))
+		if _, err := io.ReadFull(hkdf, out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(out, expected) {
+			t.Errorf("info %d: have %x, need %x", i, out, expected)
+		}
+		if have, need := hkdf.Remaining(), 255*sha256.Size-len(out); have != need {
+			t.Errorf("info %d: have %d remaining, need %d", i, have, need)
+		}
+	}
+}
+
+func TestRewind(t *testing.T) {
+	hkdf := New(sha256.New, []byte("secret"), nil, []byte("info"))
+	expected := make([]byte, 100)
+	io.ReadFull(hkdf, expected)
+
+	for _, n := range []int{0, 1, 45, 64} {
+		hkdf.Rewind()
+		io.ReadFull(hkdf, make([]byte, n))
+		hkdf.Rewind()
+
+		out := make([]byte, len(expected))
+		if _, err := io.ReadFull(hkdf, out); err != nil {
+			t.Fatalf("rewound after %d bytes: unexpected error: %v", n, err)
+		}
+		if !bytes.Equal(out, expected) {
+			t.Errorf("rewound after %d bytes: have %x, need %x", n, out, expected)
+		}
+	}
+
+	// Rewind also restarts a reader that reached the entropy limit
+	hkdf.Read(make([]byte, 255*sha256.Size))
+	hkdf.Rewind()
+	out := make([]byte, len(expected))
+	if _, err := io.ReadFull(hkdf, out); err != nil || !bytes.Equal(out, expected) {
+		t.Errorf("rewound at the entropy limit: have %x, %v", out, err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	hkdf := New(sha256.New, []byte("secret"), nil, nil)
+	if _, err := io.ReadFull(hkdf, make([]byte, 40)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prev := hkdf.prev[:cap(hkdf.prev)]
+
+	if err := hkdf.Close(); err != nil {
+		t.Errorf("unexpected error from Close: %v", err)
+	}
+	if !bytes.Equal(prev, make([]byte, len(prev))) {
+		t.Errorf("buffered output was not wiped: %x", prev)
+	}
+
+	if n, err := hkdf.Read(make([]byte, 1)); n != 0 || err != ErrClosed {
+		t.Errorf("Read after Close: n = %d, err = %v", n, err)
+	}
+	if n, err := hkdf.WriteTo(new(bytes.Buffer)); n != 0 || err != ErrClosed {
+		t.Errorf("WriteTo after Close: n = %d, err = %v", n, err)
+	}
+	hkdf.Reset(nil)
+	if r := hkdf.Remaining(); r != 0 {
+		t.Errorf("have %d remaining after Close, need 0", r)
+	}
+	if n, err := hkdf.Read(make([]byte, 1)); n != 0 || err != ErrClosed {
+		t.Errorf("Read after Close and Reset: n = %d, err = %v", n, err)
+	}
+}
+
+func TestKDF(t *testing.T) {
+	for i, tt := range hkdfTests {
+		var k KDF = NewKDF(tt.hash, tt.master, tt.salt)
+		out, err := k.DeriveKey(tt.info, len(tt.out))
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from KDF: have %v, need %v.", i, out, tt.out)
+		}
+		if _, err := k.DeriveKey(tt.info, 255*tt.hash().Size()+1); err != ErrEntropyLimit {
+			t.Errorf("test %d: have %v, need %v", i, err, ErrEntropyLimit)
+		}
+		if _, err := k.DeriveKey(tt.info, -1); err == nil {
+			t.Errorf("test %d: expected an error for a negative length", i)
+		}
+	}
+}
+
+func TestEqualPRK(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	other := Extract(sha256.New, []byte("other secret"), nil)
+
+	if !EqualPRK(prk, append([]byte{}, prk...)) {
+		t.Errorf("equal keys compared unequal")
+	}
+	if EqualPRK(prk, other) {
+		t.Errorf("different keys compared equal")
+	}
+	if EqualPRK(prk, prk[:len(prk)-1]) {
+		t.Errorf("keys of different lengths compared equal")
+	}
+}
+
+func TestExpandMany(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	requests := []KeyRequest{
+		{[]byte("enc"), 32},
+		{[]byte("mac"), 64},
+		{[]byte("iv"), 12},
+		{nil, 0},
+	}
+
+	keys, err := ExpandMany(sha256.New, prk, requests)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, req := range requests {
+		expected := make([]byte, req.Length)
+		io.ReadFull(Expand(sha256.New, prk, req.Info), expected)
+		if !bytes.Equal(keys[i], expected) {
+			t.Errorf("request %d: have %x, need %x", i, keys[i], expected)
+		}
+	}
+
+	if _, err := ExpandMany(sha256.New, prk, append(requests, KeyRequest{nil, -1})); err == nil {
+		t.Errorf("expected an error for a negative length")
+	}
+	requests = append(requests, KeyRequest{nil, 255*sha256.Size + 1})
+	if _, err := ExpandMany(sha256.New, prk, requests); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	hash := sha1.New
+	master := []byte{0x00, 0x01, 0x02, 0x03}
+	limit := hash().Size() * 255
+
+	expected := make([]byte, limit)
+	io.ReadFull(New(hash, master, nil, nil), expected)
+
+	for _, skip := range []int{0, 1, 19, 20, 21, 45, limit - 1, limit} {
+		hkdf := New(hash, master, nil, nil)
+		// Skip from both a block boundary and the middle of a block
+		for _, head := range []int{0, 3} {
+			if skip+head > limit {
+				continue
+			}
+			hkdf.Reset(nil)
+			io.ReadFull(hkdf, make([]byte, head))
+			if err := hkdf.Skip(skip); err != nil {
+				t.Fatalf("skip %d after %d: unexpected error: %v", skip, head, err)
+			}
+			out := make([]byte, hkdf.Remaining())
+			if _, err := io.ReadFull(hkdf, out); err != nil {
+				t.Fatalf("skip %d after %d: unexpected error: %v", skip, head, err)
+			}
+			if !bytes.Equal(out, expected[head+skip:]) {
+				t.Errorf("skip %d after %d: incorrect output after Skip", skip, head)
+			}
+		}
+	}
+
+	hkdf := New(hash, master, nil, nil)
+	if err := hkdf.Skip(-1); err == nil {
+		t.Errorf("expected an error for a negative skip")
+	}
+	if err := hkdf.Skip(limit + 1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if n, err := hkdf.Read(make([]byte, 1)); n != 0 || err != ErrEntropyLimit {
+		t.Errorf("Read after failed Skip: n = %d, err = %v", n, err)
+	}
+}
+
+func TestNewN(t *testing.T) {
+	for i, tt := range hkdfTests {
+		hkdf, err := NewN(tt.hash, tt.master, tt.salt, tt.info, len(tt.out))
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		out := make([]byte, len(tt.out))
+		if _, err := io.ReadFull(hkdf, out); err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from NewN: have %v, need %v.", i, out, tt.out)
+		}
+	}
+
+	limit := MaxOutputLen(sha256.New)
+	if _, err := NewN(sha256.New, nil, nil, nil, limit); err != nil {
+		t.Errorf("unexpected error at the entropy limit: %v", err)
+	}
+	if _, err := NewN(sha256.New, nil, nil, nil, limit+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := NewN(sha256.New, nil, nil, nil, -1); err == nil {
+		t.Errorf("expected an error for a negative length")
+	}
+}
+
+func TestNewSplit(t *testing.T) {
+	for i, tt := range hkdfTests {
+		out := make([]byte, len(tt.out))
+		io.ReadFull(NewSplit(tt.hash, tt.hash, tt.master, tt.salt, tt.info), out)
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from NewSplit: have %v, need %v.", i, out, tt.out)
+		}
+	}
+
+	secret, info := []byte("secret"), []byte("info")
+	expected := make([]byte, 100)
+	io.ReadFull(Expand(sha256.New, Extract(sha512.New, secret, nil), info), expected)
+	out := make([]byte, len(expected))
+	io.ReadFull(NewSplit(sha512.New, sha256.New, secret, nil, info), out)
+	if !bytes.Equal(out, expected) {
+		t.Errorf("have %x, need %x", out, expected)
+	}
+}
+
+func TestNewWithPRK(t *testing.T) {
+	for i, tt := range hkdfTests {
+		hkdf, prk := NewWithPRK(tt.hash, tt.master, tt.salt, tt.info)
+		if !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK: have %v, need %v.", i, prk, tt.prk)
+		}
+
+		out := make([]byte, len(tt.out))
+		n, err := io.ReadFull(hkdf, out)
+		if n != len(tt.out) || err != nil {
+			t.Errorf("test %d: not enough output bytes: %d.", i, n)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output: have %v, need %v.", i, out, tt.out)
+		}
+
+		// The returned PRK derives the same keys as New for other contexts
+		info := append([]byte("other "), tt.info...)
+		expected := make([]byte, len(tt.out))
+		io.ReadFull(New(tt.hash, tt.master, tt.salt, info), expected)
+		io.ReadFull(Expand(tt.hash, prk, info), out)
+		if !bytes.Equal(out, expected) {
+			t.Errorf("test %d: incorrect output for other info: have %v, need %v.", i, out, expected)
+		}
+	}
+}
+
+func TestExtractExpandMAC(t *testing.T) {
+	for i, tt := range hkdfTests {
+		mac := func(key []byte) hash.Hash { return hmac.New(tt.hash, key) }
+
+		prk := ExtractMAC(mac, tt.master, tt.salt)
+		if !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK from ExtractMAC: have %v, need %v.", i, prk, tt.prk)
+		}
+
+		out := make([]byte, len(tt.out))
+		if _, err := io.ReadFull(ExpandMAC(mac, prk, tt.info), out); err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from ExpandMAC: have %v, need %v.", i, out, tt.out)
+		}
+	}
+}
+
+func TestExpandMACBLAKE2b(t *testing.T) {
+	mac := func(key []byte) hash.Hash {
+		h, err := blake2b.New256(key)
+		if err != nil {
+			panic(err)
+		}
+		return h
+	}
+	prk := ExtractMAC(mac, []byte("secret"), nil)
+	info := []byte("info")
+
+	// T(1) = MAC(PRK, info || 0x01), T(2) = MAC(PRK, T(1) || info || 0x02)
+	m := mac(prk)
+	m.Write(info)
+	m.Write([]byte{0x01})
+	t1 := m.Sum(nil)
+	m = mac(prk)
+	m.Write(t1)
+	m.Write(info)
+	m.Write([]byte{0x02})
+	expected := m.Sum(t1)
+
+	out := make([]byte, len(expected))
+	io.ReadFull(ExpandMAC(mac, prk, info), out)
+	if !bytes.Equal(out, expected) {
+		t.Errorf("have %x, need %x", out, expected)
+	}
+}
+
+func TestExtractStrict(t *testing.T) {
+	for i, tt := range hkdfTests {
+		prk, err := ExtractStrict(tt.hash, tt.master, tt.salt)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK: have %v, need %v.", i, prk, tt.prk)
+		}
+	}
+	for _, secret := range [][]byte{nil, {}} {
+		if _, err := ExtractStrict(sha256.New, secret, nil); err == nil {
+			t.Errorf("expected an error for secret %#v", secret)
+		}
+	}
+}
+
+func TestLengths(t *testing.T) {
+	for i, tt := range hkdfTests {
+		if n := PRKLen(tt.hash); n != len(tt.prk) {
+			t.Errorf("test %d: have PRK length %d, need %d", i, n, len(tt.prk))
+		}
+		if n, r := MaxOutputLen(tt.hash), New(tt.hash, tt.master, tt.salt, tt.info).Remaining(); n != r {
+			t.Errorf("test %d: have maximum output length %d, need %d", i, n, r)
+		}
+	}
+}
+
+func TestHKDFLimitSticky(t *testing.T) {
+	hash := sha1.New
+	master := []byte{0x00, 0x01, 0x02, 0x03}
+
+	hkdf := New(hash, master, nil, nil)
+	limit := hash().Size() * 255
+	if _, err := io.ReadFull(hkdf, make([]byte, limit-5)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Once a read has failed, even reads within the budget keep failing
+	for i := 0; i < 2; i++ {
+		n, err := hkdf.Read(make([]byte, 6))
+		if n != 0 || err != ErrEntropyLimit {
+			t.Errorf("read %d past limit: n = %d, err = %v", i, n, err)
+		}
+	}
+	if n, err := hkdf.Read(make([]byte, 1)); n != 0 || err != ErrEntropyLimit {
+		t.Errorf("read within limit after error: n = %d, err = %v", n, err)
+	}
+	if r := hkdf.Remaining(); r != 0 {
+		t.Errorf("have %d remaining after error, need 0", r)
+	}
+
+	// Reset starts a new output stream, so it clears the error
+	hkdf.Reset(nil)
+	if _, err := io.ReadFull(hkdf, make([]byte, limit)); err != nil {
+		t.Errorf("unexpected error after Reset: %v", err)
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestExtractReader(t *testing.T) {
+	for i, tt := range hkdfTests {
+		prk, err := ExtractReader(tt.hash, bytes.NewReader(tt.master), tt.salt)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK: have %v, need %v.", i, prk, tt.prk)
+		}
+	}
+
+	secret := io.MultiReader(bytes.NewReader([]byte("partial")), errReader{io.ErrClosedPipe})
+	if _, err := ExtractReader(sha256.New, secret, nil); err != io.ErrClosedPipe {
+		t.Errorf("have %v, need %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestExtractMulti(t *testing.T) {
+	blake2bNew := func() hash.Hash {
+		h, _ := blake2b.New256(nil)
+		return h
+	}
+	for i, tt := range append(hkdfTests, hkdfTest{hash: blake2bNew, master: []byte("not pooled")}) {
+		expected := Extract(tt.hash, tt.master, tt.salt)
+		for split := 0; split <= len(tt.master); split += 7 {
+			prk := ExtractMulti(tt.hash, tt.salt, tt.master[:split], nil, tt.master[split:])
+			if !bytes.Equal(prk, expected) {
+				t.Errorf("test %d split at %d: have %x, need %x", i, split, prk, expected)
+			}
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	blake2bMAC := func(key []byte) hash.Hash {
+		h, _ := blake2b.New256(key)
+		return h
+	}
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("info")
+
+	for name, hkdf := range map[string]*Reader{
+		"HMAC":    Expand(sha256.New, prk, info),
+		"BLAKE2b": ExpandMAC(blake2bMAC, prk, info),
+	} {
+		expected := make([]byte, 100)
+		io.ReadFull(hkdf.Clone(), expected)
+
+		// Cloning mid-stream starts the clone from the beginning
+		head := make([]byte, 45)
+		io.ReadFull(hkdf, head)
+		clone := hkdf.Clone()
+
+		out := make([]byte, len(expected))
+		if _, err := io.ReadFull(clone, out); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !bytes.Equal(out, expected) {
+			t.Errorf("%s: incorrect output from clone: have %x, need %x", name, out, expected)
+		}
+
+		// The original is unaffected by reads from the clone
+		rest := make([]byte, len(expected)-len(head))
+		io.ReadFull(hkdf, rest)
+		if !bytes.Equal(append(head, rest...), expected) {
+			t.Errorf("%s: incorrect output from original after clone", name)
+		}
+
+		hkdf.Close()
+		if _, err := hkdf.Clone().Read(out); err != ErrClosed {
+			t.Errorf("%s: clone of closed Reader: have %v, need %v", name, err, ErrClosed)
+		}
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	hkdf := Expand(sha256.New, prk, []byte("info"))
+	out := make([]byte, 32)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		io.ReadFull(hkdf.Clone(), out)
+	}
+}
+
+func BenchmarkCloneExpand(b *testing.B) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("info")
+	out := make([]byte, 32)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		io.ReadFull(Expand(sha256.New, prk, info), out)
+	}
+}
+
+func TestExtractSalted(t *testing.T) {
+	for i, tt := range hkdfTests {
+		prk, err := ExtractSalted(tt.hash, tt.master, tt.salt)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK: have %v, need %v.", i, prk, tt.prk)
+		}
+	}
+
+	for _, salt := range [][]byte{{0x00}, make([]byte, sha256.Size)} {
+		if _, err := ExtractSalted(sha256.New, []byte("secret"), salt); err == nil {
+			t.Errorf("expected an error for salt %x", salt)
+		}
+	}
+	salt := make([]byte, sha256.Size)
+	salt[len(salt)-1] = 1
+	if _, err := ExtractSalted(sha256.New, []byte("secret"), salt); err != nil {
+		t.Errorf("unexpected error for salt %x: %v", salt, err)
+	}
+}
+
+func TestCheckHash(t *testing.T) {
+	for i, tt := range hkdfTests {
+		if err := CheckHash(tt.hash); err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+	}
+
+	nilHash := func() hash.Hash { return nil }
+	for name, h := range map[string]func() hash.Hash{
+		"nil constructor": nil,
+		"nil hash":        nilHash,
+	} {
+		if err := CheckHash(h); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		for fn, f := range map[string]func(){
+			"Extract": func() { Extract(h, nil, nil) },
+			"Expand":  func() { Expand(h, nil, nil) },
+			"New":     func() { New(h, nil, nil, nil) },
+		} {
+			func() {
+				defer func() {
+					msg, _ := recover().(string)
+					if !strings.HasPrefix(msg, "hkdf: hash constructor") {
+						t.Errorf("%s: %s: unexpected panic %q", fn, name, msg)
+					}
+				}()
+				f()
+			}()
+		}
+	}
+}
+
+// shakeHash adapts SHAKE128 to hash.Hash the way a careless caller might, with
+// an output size of zero.
+type shakeHash struct {
+	sha3.ShakeHash
+}
+
+func (h shakeHash) Sum(b []byte) []byte { return b }
+func (h shakeHash) Size() int           { return 0 }
+func (h shakeHash) BlockSize() int      { return 168 }
+
+func TestNoFixedSizeHash(t *testing.T) {
+	h := func() hash.Hash { return shakeHash{sha3.NewShake128()} }
+	if err := CheckHash(h); err == nil || err.Error() != errNoFixedSize {
+		t.Errorf("have %v, need %q", err, errNoFixedSize)
+	}
+	for fn, f := range map[string]func(){
+		"Extract":   func() { Extract(h, []byte("secret"), nil) },
+		"Expand":    func() { Expand(h, []byte("prk"), nil) },
+		"New":       func() { New(h, []byte("secret"), []byte("salt"), nil) },
+		"ExpandMAC": func() { ExpandMAC(func([]byte) hash.Hash { return h() }, nil, nil) },
+	} {
+		func() {
+			defer func() {
+				if msg, _ := recover().(string); msg != errNoFixedSize {
+					t.Errorf("%s: unexpected panic %q", fn, msg)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestExpandInto(t *testing.T) {
+	for i, tt := range hkdfTests {
+		out := make([]byte, len(tt.out))
+		if err := ExpandInto(tt.hash, tt.prk, tt.info, out); err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from ExpandInto: have %v, need %v.", i, out, tt.out)
+		}
+	}
+
+	prk := Extract(sha1.New, []byte("secret"), nil)
+	for _, size := range []int{0, 1, 20, 21, 40, 255 * 20} {
+		expected := make([]byte, size)
+		io.ReadFull(Expand(sha1.New, prk, nil), expected)
+
+		// Spare capacity after out must not be written to
+		buf := make([]byte, size, size+20)
+		if err := ExpandInto(sha1.New, prk, nil, buf); err != nil {
+			t.Errorf("size %d: unexpected error: %v", size, err)
+		}
+		if !bytes.Equal(buf, expected) {
+			t.Errorf("size %d: incorrect output", size)
+		}
+		if spare := buf[size:cap(buf)]; !bytes.Equal(spare, make([]byte, len(spare))) {
+			t.Errorf("size %d: wrote past the end of out", size)
+		}
+	}
+
+	out := make([]byte, 255*20+1)
+	if err := ExpandInto(sha1.New, prk, nil, out); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if !bytes.Equal(out, make([]byte, len(out))) {
+		t.Errorf("output written despite the entropy limit")
+	}
+}
+
+func TestExtractBatch(t *testing.T) {
+	for _, h := range []func() hash.Hash{sha256.New, func() hash.Hash { return sha256.New() }} {
+		secret := []byte("master secret")
+		salts := [][]byte{nil, []byte("tenant 1"), []byte("tenant 2"), bytes.Repeat([]byte{1}, 100)}
+		prks := ExtractBatch(h, secret, salts)
+		if len(prks) != len(salts) {
+			t.Fatalf("have %d PRKs, need %d", len(prks), len(salts))
+		}
+		for i, salt := range salts {
+			if need := Extract(sha256.New, secret, salt); !bytes.Equal(prks[i], need) {
+				t.Errorf("salt %d: have %x, need %x", i, prks[i], need)
+			}
+		}
+
+		// Appending to one PRK must not overwrite the next
+		_ = append(prks[0], 0xff)
+		if need := Extract(sha256.New, secret, salts[1]); !bytes.Equal(prks[1], need) {
+			t.Errorf("PRK overwritten by appending to the previous one")
+		}
+	}
+
+	if prks := ExtractBatch(sha256.New, nil, nil); len(prks) != 0 {
+		t.Errorf("have %d PRKs for no salts", len(prks))
+	}
+}
+
+func TestExtractAppend(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	for i, tt := range hkdfTests {
+		prefix := []byte("prefix")
+		out := ExtractAppend(prefix, tt.hash, tt.master, tt.salt)
+		if !bytes.Equal(out, append([]byte("prefix"), tt.prk...)) {
+			t.Errorf("test %d: incorrect output from ExtractAppend: have %x", i, out)
+		}
+
+		// Reusing a buffer with enough capacity must not reallocate
+		buf = ExtractAppend(buf[:0], tt.hash, tt.master, tt.salt)
+		if !bytes.Equal(buf, Extract(tt.hash, tt.master, tt.salt)) {
+			t.Errorf("test %d: incorrect output from ExtractAppend: have %x", i, buf)
+		}
+		if cap(buf) != 64 {
+			t.Errorf("test %d: buffer was reallocated", i)
+		}
+	}
+}
+
+func TestNextBlock(t *testing.T) {
+	for i, tt := range hkdfTests {
+		size := tt.hash().Size()
+		r := Expand(tt.hash, tt.prk, tt.info)
+		var out []byte
+		for j := 1; len(out) < len(tt.out); j++ {
+			block, index, err := r.NextBlock()
+			if err != nil {
+				t.Fatalf("test %d: unexpected error: %v", i, err)
+			}
+			if int(index) != j || len(block) != size {
+				t.Errorf("test %d: have block %d of %d bytes, need block %d of %d bytes", i, index, len(block), j, size)
+			}
+			out = append(out, block...)
+		}
+		if !bytes.Equal(out[:len(tt.out)], tt.out) {
+			t.Errorf("test %d: incorrect output from NextBlock: have %x", i, out)
+		}
+	}
+
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	r := Expand(sha256.New, prk, nil)
+	first := make([]byte, sha256.Size)
+	io.ReadFull(Expand(sha256.New, prk, nil), first)
+
+	// A partially read block is discarded
+	r.Read(make([]byte, 1))
+	block, index, err := r.NextBlock()
+	if err != nil || index != 2 || bytes.Equal(block, first) {
+		t.Errorf("unexpected block %d after a partial read: %x, %v", index, block, err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err != nil || r.Remaining() != 253*sha256.Size-1 {
+		t.Errorf("Read is not aligned after NextBlock: %v, %d remaining", err, r.Remaining())
+	}
+
+	for index != 255 {
+		if _, index, err = r.NextBlock(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, _, err := r.NextBlock(); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := r.Read(make([]byte, 1)); err != ErrEntropyLimit {
+		t.Errorf("Read after NextBlock: have %v, need %v", err, ErrEntropyLimit)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	secret := []byte("0123456789abcdef")
+	prk := Extract(sha256.New, secret, nil)
+	e := NewExtractor(sha256.New, nil)
+	e.Write(secret)
+	for _, v := range []interface{}{
+		New(sha256.New, secret, nil, nil),
+		Expand(sha256.New, prk, secret),
+		ExpandWide(sha256.New, prk, secret, 2),
+		NewReaderAt(sha256.New, prk, nil),
+		NewKDF(sha256.New, secret, nil),
+		e,
+		NewChain(sha256.New, secret),
+		NewNonceGen(sha256.New, prk, nil, 12),
+	} {
+		embedded := struct{ V interface{} }{v}
+		for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+			out := fmt.Sprintf(format, embedded)
+			if !strings.Contains(out, "<redacted>") {
+				t.Errorf("%T: %s: output is not redacted: %s", v, format, out)
+			}
+			for _, s := range [][]byte{secret, prk} {
+				if strings.Contains(out, string(s)) || strings.Contains(out, fmt.Sprintf("%x", s)) {
+					t.Errorf("%T: %s: output leaks key material: %s", v, format, out)
+				}
+			}
+		}
+	}
+}
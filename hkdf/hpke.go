@@ -0,0 +1,123 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+	"io"
+)
+
+// hpkeVersion is the protocol version label of RFC 9180, Section 4.
+const hpkeVersion = "HPKE-v1"
+
+// LabeledExtract implements the LabeledExtract function of RFC 9180, Section
+// 4, which extracts a pseudorandom key from ikm prefixed with the HPKE version
+// string, the suite identifier and label.
+func LabeledExtract(hash func() hash.Hash, salt, label, ikm, suiteID []byte) []byte {
+	labeledIKM := make([]byte, 0, len(hpkeVersion)+len(suiteID)+len(label)+len(ikm))
+	labeledIKM = append(labeledIKM, hpkeVersion...)
+	labeledIKM = append(labeledIKM, suiteID...)
+	labeledIKM = append(labeledIKM, label...)
+	labeledIKM = append(labeledIKM, ikm...)
+	return Extract(hash, labeledIKM, salt)
+}
+
+// LabeledExpand implements the LabeledExpand function of RFC 9180, Section 4,
+// which expands length bytes from prk using an info structure made of the
+// two-byte output length, the HPKE version string, the suite identifier,
+// label and info.
+func LabeledExpand(hash func() hash.Hash, prk, label, info []byte, suiteID []byte, length int) ([]byte, error) {
+	if length < 0 || length > 0xffff {
+		return nil, errors.New("hkdf: invalid HPKE output length")
+	}
+	labeledInfo := make([]byte, 0, 2+len(hpkeVersion)+len(suiteID)+len(label)+len(info))
+	labeledInfo = append(labeledInfo, byte(length>>8), byte(length))
+	labeledInfo = append(labeledInfo, hpkeVersion...)
+	labeledInfo = append(labeledInfo, suiteID...)
+	labeledInfo = append(labeledInfo, label...)
+	labeledInfo = append(labeledInfo, info...)
+
+	out := make([]byte, length)
+	if _, err := io.ReadFull(Expand(hash, prk, labeledInfo), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HPKE modes of RFC 9180, Section 5.
+const (
+	HPKEModeBase    byte = 0x00
+	HPKEModePSK     byte = 0x01
+	HPKEModeAuth    byte = 0x02
+	HPKEModeAuthPSK byte = 0x03
+)
+
+// hpkeAEADSizes returns the key and nonce lengths Nk and Nn of the AEAD
+// identified in the HPKE suite identifier suiteID, the concatenation of
+// "HPKE" and the two-byte KEM, KDF and AEAD identifiers.
+func hpkeAEADSizes(suiteID []byte) (nk, nn int, err error) {
+	if len(suiteID) != 10 || string(suiteID[:4]) != "HPKE" {
+		return 0, 0, errors.New("hkdf: invalid HPKE suite identifier")
+	}
+	switch uint16(suiteID[8])<<8 | uint16(suiteID[9]) {
+	case 0x0001: // AES-128-GCM
+		return 16, 12, nil
+	case 0x0002, 0x0003: // AES-256-GCM, ChaCha20Poly1305
+		return 32, 12, nil
+	case 0xffff: // Export-only
+		return 0, 0, nil
+	}
+	return 0, 0, errors.New("hkdf: unsupported HPKE AEAD identifier")
+}
+
+// HPKEKeySchedule implements the KeySchedule function of RFC 9180, Section
+// 5.1. It derives the AEAD key, the base nonce and the exporter secret of an
+// HPKE context from the KEM shared secret, using the KDF hash. The lengths of
+// the key and nonce are those of the AEAD identified in suiteID; for the
+// export-only AEAD, key and baseNonce are nil.
+//
+// The psk and pskID must both be empty in HPKEModeBase and HPKEModeAuth, and
+// both be non-empty in HPKEModePSK and HPKEModeAuthPSK.
+func HPKEKeySchedule(hash func() hash.Hash, suiteID, sharedSecret, info, psk, pskID []byte, mode byte) (key, baseNonce, exporterSecret []byte, err error) {
+	nk, nn, err := hpkeAEADSizes(suiteID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if mode > HPKEModeAuthPSK {
+		return nil, nil, nil, errors.New("hkdf: invalid HPKE mode")
+	}
+	gotPSK, gotPSKID := len(psk) > 0, len(pskID) > 0
+	switch needPSK := mode == HPKEModePSK || mode == HPKEModeAuthPSK; {
+	case gotPSK != gotPSKID:
+		return nil, nil, nil, errors.New("hkdf: inconsistent HPKE PSK inputs")
+	case gotPSK && !needPSK:
+		return nil, nil, nil, errors.New("hkdf: HPKE PSK input provided when not needed")
+	case !gotPSK && needPSK:
+		return nil, nil, nil, errors.New("hkdf: missing required HPKE PSK input")
+	}
+
+	pskIDHash := LabeledExtract(hash, nil, []byte("psk_id_hash"), pskID, suiteID)
+	infoHash := LabeledExtract(hash, nil, []byte("info_hash"), info, suiteID)
+	context := make([]byte, 0, 1+len(pskIDHash)+len(infoHash))
+	context = append(context, mode)
+	context = append(context, pskIDHash...)
+	context = append(context, infoHash...)
+
+	secret := LabeledExtract(hash, sharedSecret, []byte("secret"), psk, suiteID)
+	if nk > 0 {
+		if key, err = LabeledExpand(hash, secret, []byte("key"), context, suiteID, nk); err != nil {
+			return nil, nil, nil, err
+		}
+		if baseNonce, err = LabeledExpand(hash, secret, []byte("base_nonce"), context, suiteID, nn); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	exporterSecret, err = LabeledExpand(hash, secret, []byte("exp"), context, suiteID, len(secret))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return key, baseNonce, exporterSecret, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+// hpkeBaseVector is the key schedule of RFC 9180, Appendix A.1.1:
+// DHKEM(X25519, HKDF-SHA256), HKDF-SHA256, AES-128-GCM in base mode.
+var hpkeBaseVector = struct {
+	suiteID            []byte
+	info               []byte
+	sharedSecret       []byte
+	keyScheduleContext []byte
+	secret             []byte
+	key                []byte
+	baseNonce          []byte
+	exporterSecret     []byte
+}{
+	suiteID:            []byte("HPKE\x00\x20\x00\x01\x00\x01"),
+	info:               mustDecodeHex("4f6465206f6e2061204772656369616e2055726e"),
+	sharedSecret:       mustDecodeHex("fe0e18c9f024ce43799ae393c7e8fe8fce9d218875e8227b0187c04e7d2ea1fc"),
+	keyScheduleContext: mustDecodeHex("00725611c9d98c07c03f60095cd32d400d8347d45ed67097bbad50fc56da742d07cb6cffde367bb0565ba28bb02c90744a20f5ef37f30523526106f637abb05449"),
+	secret:             mustDecodeHex("12fff91991e93b48de37e7daddb52981084bd8aa64289c3788471d9a9712f397"),
+	key:                mustDecodeHex("4531685d41d65f03dc48f6b8302c05b0"),
+	baseNonce:          mustDecodeHex("56d890e5accaaf011cff4b7d"),
+	exporterSecret:     mustDecodeHex("45ff1c2e220db587171952c0592d5f5ebe103f1561a2614e38f2ffd47e99e3f8"),
+}
+
+func TestLabeledExtractExpand(t *testing.T) {
+	v := hpkeBaseVector
+	hash := sha256.New
+
+	pskIDHash := LabeledExtract(hash, nil, []byte("psk_id_hash"), nil, v.suiteID)
+	infoHash := LabeledExtract(hash, nil, []byte("info_hash"), v.info, v.suiteID)
+	context := append(append([]byte{0x00}, pskIDHash...), infoHash...)
+	if !bytes.Equal(context, v.keyScheduleContext) {
+		t.Errorf("incorrect key schedule context: have %x, need %x", context, v.keyScheduleContext)
+	}
+
+	secret := LabeledExtract(hash, v.sharedSecret, []byte("secret"), nil, v.suiteID)
+	if !bytes.Equal(secret, v.secret) {
+		t.Errorf("incorrect secret: have %x, need %x", secret, v.secret)
+	}
+
+	for _, tt := range []struct {
+		label string
+		out   []byte
+	}{
+		{"key", v.key},
+		{"base_nonce", v.baseNonce},
+		{"exp", v.exporterSecret},
+	} {
+		out, err := LabeledExpand(hash, secret, []byte(tt.label), context, v.suiteID, len(tt.out))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.label, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("%s: have %x, need %x", tt.label, out, tt.out)
+		}
+	}
+}
+
+func TestLabeledExpandLimit(t *testing.T) {
+	prk := make([]byte, sha256.Size)
+	if _, err := LabeledExpand(sha256.New, prk, nil, nil, nil, 0x10000); err == nil {
+		t.Errorf("expected an error for an output length over 2^16-1")
+	}
+	if _, err := LabeledExpand(sha256.New, prk, nil, nil, nil, 255*sha256.Size+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+}
+
+func TestHPKEKeySchedule(t *testing.T) {
+	v := hpkeBaseVector
+	key, baseNonce, exporterSecret, err := HPKEKeySchedule(sha256.New, v.suiteID, v.sharedSecret, v.info, nil, nil, HPKEModeBase)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(key, v.key) {
+		t.Errorf("incorrect key: have %x, need %x", key, v.key)
+	}
+	if !bytes.Equal(baseNonce, v.baseNonce) {
+		t.Errorf("incorrect base nonce: have %x, need %x", baseNonce, v.baseNonce)
+	}
+	if !bytes.Equal(exporterSecret, v.exporterSecret) {
+		t.Errorf("incorrect exporter secret: have %x, need %x", exporterSecret, v.exporterSecret)
+	}
+
+	// The export-only AEAD derives only the exporter secret
+	exportOnly := []byte("HPKE\x00\x20\x00\x01\xff\xff")
+	key, baseNonce, exporterSecret, err = HPKEKeySchedule(sha256.New, exportOnly, v.sharedSecret, v.info, nil, nil, HPKEModeBase)
+	if err != nil || key != nil || baseNonce != nil || len(exporterSecret) != sha256.Size {
+		t.Errorf("export-only: key = %x, baseNonce = %x, exporterSecret = %x, err = %v", key, baseNonce, exporterSecret, err)
+	}
+
+	psk, pskID := []byte("psk"), []byte("psk id")
+	for _, tt := range []struct {
+		name       string
+		suiteID    []byte
+		psk, pskID []byte
+		mode       byte
+	}{
+		{"short suite", v.suiteID[:8], nil, nil, HPKEModeBase},
+		{"unknown AEAD", []byte("HPKE\x00\x20\x00\x01\x00\x04"), nil, nil, HPKEModeBase},
+		{"invalid mode", v.suiteID, nil, nil, 0x04},
+		{"PSK without ID", v.suiteID, psk, nil, HPKEModePSK},
+		{"ID without PSK", v.suiteID, nil, pskID, HPKEModePSK},
+		{"unneeded PSK", v.suiteID, psk, pskID, HPKEModeBase},
+		{"unneeded auth PSK", v.suiteID, psk, pskID, HPKEModeAuth},
+		{"missing PSK", v.suiteID, nil, nil, HPKEModeAuthPSK},
+	} {
+		if _, _, _, err := HPKEKeySchedule(sha256.New, tt.suiteID, v.sharedSecret, v.info, tt.psk, tt.pskID, tt.mode); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+	if _, _, _, err := HPKEKeySchedule(sha256.New, v.suiteID, v.sharedSecret, v.info, psk, pskID, HPKEModePSK); err != nil {
+		t.Errorf("PSK mode: unexpected error: %v", err)
+	}
+}
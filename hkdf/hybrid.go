@@ -0,0 +1,63 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"io"
+)
+
+// HybridExtract extracts a pseudorandom key from two shared secrets, such as
+// those of a classical and a post-quantum KEM, by using their concatenation
+// ss1 || ss2 as the secret of Extract. The lengths of the secrets must be
+// fixed by the scheme, as the concatenation is otherwise ambiguous.
+//
+// The result is pseudorandom as long as either secret is, under the
+// assumption that HMAC is a dual PRF; see Bindel, Brendel, Fischlin, Goncalves
+// and Stebila, "Hybrid Key Encapsulation Mechanisms and Authenticated Key
+// Exchange" (PQCrypto 2019). Giacon, Heuer and Poettering, "KEM Combiners"
+// (PKC 2018), show that a combiner preserves IND-CCA security only if it also
+// binds the KEM ciphertexts, so these should be included in salt, or in the
+// info of subsequent Expand calls.
+func HybridExtract(hash func() hash.Hash, ss1, ss2, salt []byte) []byte {
+	return ExtractMulti(hash, salt, ss1, ss2)
+}
+
+// Combine returns a reader whose output is the exclusive or of the outputs of
+// Expand for prk1 and prk2 with the same info. The output is pseudorandom as
+// long as either key is, but unlike HybridExtract this combiner does not bind
+// any KEM ciphertexts, and does not preserve IND-CCA security on its own. To
+// concatenate the outputs instead, use io.MultiReader with two Readers.
+//
+// Like a Reader, it returns ErrEntropyLimit, and reads nothing, if fewer than
+// len(p) bytes remain.
+func Combine(hash func() hash.Hash, prk1, prk2, info []byte) io.Reader {
+	return &xorReader{a: Expand(hash, prk1, info), b: Expand(hash, prk2, info)}
+}
+
+// xorReader reads the exclusive or of the outputs of a and b.
+type xorReader struct {
+	a, b *Reader
+	buf  []byte
+}
+
+func (x *xorReader) Read(p []byte) (int, error) {
+	if cap(x.buf) < len(p) {
+		x.buf = make([]byte, len(p))
+	}
+	buf := x.buf[:len(p)]
+	n, err := x.a.Read(p)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := x.b.Read(buf[:n]); err != nil {
+		return 0, err
+	}
+	for i := range buf[:n] {
+		p[i] ^= buf[i]
+		buf[i] = 0
+	}
+	return n, nil
+}
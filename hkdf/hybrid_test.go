@@ -0,0 +1,58 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestHybridExtract(t *testing.T) {
+	ss1 := bytes.Repeat([]byte{0x01}, 32)
+	ss2 := bytes.Repeat([]byte{0x02}, 32)
+	salt := []byte("ciphertexts")
+
+	prk := HybridExtract(sha256.New, ss1, ss2, salt)
+	if expected := Extract(sha256.New, append(append([]byte{}, ss1...), ss2...), salt); !bytes.Equal(prk, expected) {
+		t.Errorf("have %x, need %x", prk, expected)
+	}
+	if bytes.Equal(prk, HybridExtract(sha256.New, ss2, ss1, salt)) {
+		t.Errorf("swapped secrets extracted the same PRK")
+	}
+}
+
+func TestCombine(t *testing.T) {
+	prk1 := Extract(sha256.New, []byte("classical"), nil)
+	prk2 := Extract(sha256.New, []byte("post-quantum"), nil)
+	info := []byte("hybrid")
+
+	out1 := make([]byte, 100)
+	out2 := make([]byte, 100)
+	io.ReadFull(Expand(sha256.New, prk1, info), out1)
+	io.ReadFull(Expand(sha256.New, prk2, info), out2)
+	for i := range out1 {
+		out1[i] ^= out2[i]
+	}
+
+	// Read in uneven pieces to cross block boundaries
+	r := Combine(sha256.New, prk1, prk2, info)
+	var out []byte
+	for _, n := range []int{1, 40, 59} {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out = append(out, buf...)
+	}
+	if !bytes.Equal(out, out1) {
+		t.Errorf("have %x, need %x", out, out1)
+	}
+
+	if _, err := r.Read(make([]byte, 255*sha256.Size)); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+// Info builds an info value from parts that cannot be confused with the info
+// built from any other sequence of parts, unlike their plain concatenation,
+// where for example "ab" followed by "c" equals "a" followed by "bc".
+//
+// Each part is encoded as its length, as a 32-bit big-endian integer,
+// followed by its contents, and the encoded parts are concatenated in order.
+// Info with no parts returns an empty, non-nil slice. Info panics if a part
+// is 2^32 bytes or longer.
+func Info(parts ...[]byte) []byte {
+	n := 0
+	for _, part := range parts {
+		if uint64(len(part)) > 0xffffffff {
+			panic("hkdf: info part too long")
+		}
+		n += 4 + len(part)
+	}
+	info := make([]byte, 0, n)
+	for _, part := range parts {
+		info = appendDatalen(info, part)
+	}
+	return info
+}
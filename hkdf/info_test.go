@@ -0,0 +1,28 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInfo(t *testing.T) {
+	info := Info([]byte("ab"), nil, []byte("c"))
+	expected := []byte("\x00\x00\x00\x02ab\x00\x00\x00\x00\x00\x00\x00\x01c")
+	if !bytes.Equal(info, expected) {
+		t.Errorf("have %q, need %q", info, expected)
+	}
+
+	if bytes.Equal(Info([]byte("ab"), []byte("c")), Info([]byte("a"), []byte("bc"))) {
+		t.Errorf("ambiguous info for differently split parts")
+	}
+	if bytes.Equal(Info([]byte("a")), Info([]byte("a"), nil)) {
+		t.Errorf("ambiguous info for a trailing empty part")
+	}
+	if info := Info(); info == nil || len(info) != 0 {
+		t.Errorf("have %q, need an empty slice", info)
+	}
+}
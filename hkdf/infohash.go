@@ -0,0 +1,33 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "hash"
+
+// An InfoHasher computes an info value for Expand as the running hash of a
+// protocol transcript, which is written to it as the protocol progresses,
+// implementing io.Writer. Its hash is independent of the one used for HKDF,
+// although protocols such as TLS 1.3 use the same hash for both; see
+// ExpandLabel for an example.
+type InfoHasher struct {
+	h hash.Hash
+}
+
+// NewInfoHasher returns an InfoHasher hashing the transcript with hash.
+func NewInfoHasher(hash func() hash.Hash) *InfoHasher {
+	return &InfoHasher{hash()}
+}
+
+// Write appends p to the transcript. It never returns an error.
+func (h *InfoHasher) Write(p []byte) (int, error) {
+	return h.h.Write(p)
+}
+
+// Sum returns the hash of the transcript written so far, to be used as info.
+// It does not change the state of h, so that the transcript can be extended
+// and hashed again.
+func (h *InfoHasher) Sum() []byte {
+	return h.h.Sum(nil)
+}
@@ -0,0 +1,33 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestInfoHasher(t *testing.T) {
+	h := NewInfoHasher(sha256.New)
+	h.Write([]byte("client hello"))
+	first := sha256.Sum256([]byte("client hello"))
+	if info := h.Sum(); !bytes.Equal(info, first[:]) {
+		t.Errorf("have %x, need %x", info, first)
+	}
+
+	h.Write([]byte("server hello"))
+	second := sha256.Sum256([]byte("client helloserver hello"))
+	if info := h.Sum(); !bytes.Equal(info, second[:]) {
+		t.Errorf("have %x after extending the transcript, need %x", info, second)
+	}
+
+	// The transcript hash matches the context hashed by DeriveSecret
+	secret := Extract(sha256.New, []byte("secret"), nil)
+	out := ExpandLabel(sha256.New, secret, "c hs traffic", h.Sum(), sha256.Size)
+	if expected := DeriveSecret(sha256.New, secret, "c hs traffic", []byte("client helloserver hello")); !bytes.Equal(out, expected) {
+		t.Errorf("have %x, need %x", out, expected)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+)
+
+// DeriveUint64 returns the first 8 bytes of output of Expand for the
+// pseudorandom key and info, decoded as a big-endian integer.
+func DeriveUint64(hash func() hash.Hash, prk, info []byte) uint64 {
+	var b [8]byte
+	if err := ExpandInto(hash, prk, info, b[:]); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// DeriveIntn returns an integer in [0, n) derived from the pseudorandom key
+// and info, without modulo bias. It panics if n <= 0.
+//
+// The output of Expand is read as a sequence of 8-byte big-endian integers,
+// the first of which is DeriveUint64, and the first one v not below
+// 2^64 mod n is returned as v mod n. Each integer is rejected with a
+// probability below 1/2, and usually far below, so fewer than two are read
+// on average.
+func DeriveIntn(hash func() hash.Hash, prk, info []byte, n int) int {
+	if n <= 0 {
+		panic("hkdf: invalid argument to DeriveIntn")
+	}
+	max := uint64(n)
+	threshold := -max % max // 2^64 mod n
+	r := Expand(hash, prk, info)
+	var b [8]byte
+	for {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			// Only reachable with a probability below 2^-(255*Size/8).
+			panic("hkdf: " + err.Error())
+		}
+		if v := binary.BigEndian.Uint64(b[:]); v >= threshold {
+			return int(v % max)
+		}
+	}
+}
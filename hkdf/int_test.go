@@ -0,0 +1,55 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestDeriveUint64(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	b := make([]byte, 8)
+	io.ReadFull(Expand(sha256.New, prk, []byte("shard")), b)
+	if v, need := DeriveUint64(sha256.New, prk, []byte("shard")), binary.BigEndian.Uint64(b); v != need {
+		t.Errorf("have %#x, need %#x", v, need)
+	}
+}
+
+func TestDeriveIntn(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	for _, n := range []int{1, 2, 3, 10, 1000, 1<<30 + 1, int(^uint(0) >> 1)} {
+		counts := make(map[int]int)
+		for i := 0; i < 200; i++ {
+			info := []byte(fmt.Sprintf("key %d", i))
+			v := DeriveIntn(sha256.New, prk, info, n)
+			if v < 0 || v >= n {
+				t.Fatalf("n = %d: %d out of range", n, v)
+			}
+			if v != DeriveIntn(sha256.New, prk, info, n) {
+				t.Fatalf("n = %d: nondeterministic output", n)
+			}
+			counts[v]++
+		}
+		if n == 2 && (counts[0] < 50 || counts[1] < 50) {
+			t.Errorf("n = 2: skewed distribution %v", counts)
+		}
+	}
+
+	// When the first integer is accepted, the result is DeriveUint64 mod n
+	if v, need := DeriveIntn(sha256.New, prk, nil, 16), int(DeriveUint64(sha256.New, prk, nil)%16); v != need {
+		t.Errorf("have %d, need %d", v, need)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for n = 0")
+		}
+	}()
+	DeriveIntn(sha256.New, prk, nil, 0)
+}
@@ -0,0 +1,29 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+// JOSEInfo builds the OtherInfo structure of RFC 7518, Section 4.6.2, for use
+// as the info of a direct key agreement with HKDF (see RFC 8619 for COSE).
+//
+// The AlgorithmID, PartyUInfo and PartyVInfo fields are encoded as a 32-bit
+// big-endian length followed by their contents. suppPubInfo and suppPrivInfo
+// are appended as is, so suppPubInfo should already contain the encoded key
+// data length in bits, as a 32-bit big-endian integer.
+func JOSEInfo(algID string, partyU, partyV, suppPubInfo, suppPrivInfo []byte) []byte {
+	info := make([]byte, 0, 12+len(algID)+len(partyU)+len(partyV)+len(suppPubInfo)+len(suppPrivInfo))
+	info = appendDatalen(info, []byte(algID))
+	info = appendDatalen(info, partyU)
+	info = appendDatalen(info, partyV)
+	info = append(info, suppPubInfo...)
+	info = append(info, suppPrivInfo...)
+	return info
+}
+
+// appendDatalen appends data to b, prefixed with its 32-bit big-endian length.
+func appendDatalen(b, data []byte) []byte {
+	n := len(data)
+	b = append(b, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	return append(b, data...)
+}
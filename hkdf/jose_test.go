@@ -0,0 +1,30 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJOSEInfo(t *testing.T) {
+	// RFC 7518, Appendix C
+	expected := []byte{
+		0, 0, 0, 7, 65, 49, 50, 56, 71, 67, 77,
+		0, 0, 0, 5, 65, 108, 105, 99, 101,
+		0, 0, 0, 3, 66, 111, 98,
+		0, 0, 0, 128,
+	}
+	info := JOSEInfo("A128GCM", []byte("Alice"), []byte("Bob"), []byte{0, 0, 0, 128}, nil)
+	if !bytes.Equal(info, expected) {
+		t.Errorf("have %v, need %v", info, expected)
+	}
+
+	// Empty party information is still length prefixed
+	expected = []byte{0, 0, 0, 1, 'x', 0, 0, 0, 0, 0, 0, 0, 0, 1, 2}
+	if info := JOSEInfo("x", nil, nil, nil, []byte{1, 2}); !bytes.Equal(info, expected) {
+		t.Errorf("have %v, need %v", info, expected)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"io"
+)
+
+// keyIDLabel is the first part of the info of key identifiers, which
+// separates them from the key material expanded with the caller's info.
+const keyIDLabel = "hkdf key id"
+
+// KeyID derives an identifier of idLen bytes for the key expanded from prk
+// with info, so that systems can refer to derived keys without storing them.
+// It is expanded from prk with the info built by Info from a fixed label and
+// info, so it does not overlap the output of Expand with the same info.
+//
+// The identifier reveals nothing about the key, but it is deterministic, so
+// it links every use of the same prk and info. KeyID panics if idLen is
+// negative or larger than the amount of key material that can be derived with
+// hash.
+func KeyID(hash func() hash.Hash, prk, info []byte, idLen int) []byte {
+	if idLen < 0 {
+		panic("hkdf: negative key identifier length")
+	}
+	id := make([]byte, idLen)
+	if _, err := io.ReadFull(Expand(hash, prk, Info([]byte(keyIDLabel), info)), id); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	return id
+}
+
+// ExpandWithHeader is like Expand, but the returned stream starts with the
+// headerLen bytes of KeyID for prk and info, followed by the output of Expand,
+// for self-describing file formats. It returns ErrEntropyLimit if headerLen is
+// larger than the amount of key material that can be derived with hash.
+func ExpandWithHeader(hash func() hash.Hash, prk, info []byte, headerLen int) (io.Reader, error) {
+	if headerLen < 0 {
+		return nil, errors.New("hkdf: negative header length")
+	}
+	if headerLen > MaxOutputLen(hash) {
+		return nil, ErrEntropyLimit
+	}
+	header := KeyID(hash, prk, info, headerLen)
+	return io.MultiReader(bytes.NewReader(header), Expand(hash, prk, info)), nil
+}
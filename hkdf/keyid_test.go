@@ -0,0 +1,61 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestKeyID(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("file key")
+	key := make([]byte, 32)
+	io.ReadFull(Expand(sha256.New, prk, info), key)
+
+	id := KeyID(sha256.New, prk, info, 16)
+	if len(id) != 16 {
+		t.Fatalf("have length %d, need 16", len(id))
+	}
+	if bytes.Equal(id, key[:16]) {
+		t.Errorf("key identifier overlaps the key material")
+	}
+	if !bytes.Equal(id, KeyID(sha256.New, prk, info, 16)) {
+		t.Errorf("key identifier is not deterministic")
+	}
+	if bytes.Equal(id, KeyID(sha256.New, prk, []byte("other key"), 16)) {
+		t.Errorf("different info gave the same key identifier")
+	}
+
+	r, err := ExpandWithHeader(sha256.New, prk, info, 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := make([]byte, 16+len(key))
+	if _, err := io.ReadFull(r, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(out[:16], id) || !bytes.Equal(out[16:], key) {
+		t.Errorf("incorrect output: %x", out)
+	}
+
+	if _, err := ExpandWithHeader(sha256.New, prk, info, MaxOutputLen(sha256.New)+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := ExpandWithHeader(sha256.New, prk, info, -1); err == nil {
+		t.Errorf("expected an error for a negative header length")
+	}
+}
+
+func TestKeyIDNegativeLength(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "hkdf: negative key identifier length" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	KeyID(sha256.New, make([]byte, sha256.Size), nil, -1)
+}
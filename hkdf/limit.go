@@ -0,0 +1,61 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+	"io"
+)
+
+// DeriveLimited is like New, but the returned reader produces at most maxLen
+// bytes, after which it returns io.EOF. Unlike a Reader, it can be read until
+// EOF by functions such as io.Copy and io.ReadAll. It returns ErrEntropyLimit
+// if maxLen is longer than the limit for hash.
+func DeriveLimited(hash func() hash.Hash, secret, salt, info []byte, maxLen int) (io.Reader, error) {
+	if maxLen < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	if maxLen > MaxOutputLen(hash) {
+		return nil, ErrEntropyLimit
+	}
+	return &boundedReader{New(hash, secret, salt, info), int64(maxLen)}, nil
+}
+
+// LimitReader returns a reader that reads from r but stops with io.EOF after
+// n bytes, like io.LimitReader.
+//
+// Unlike io.LimitReader, if r is a *Reader whose entropy limit is reached
+// before n bytes were read, the returned reader first returns all the output
+// that remains, and then ErrEntropyLimit instead of io.EOF. Functions such as
+// io.ReadAll therefore report an error, rather than silently returning fewer
+// than n bytes.
+func LimitReader(r io.Reader, n int64) io.Reader {
+	return &boundedReader{r, n}
+}
+
+// boundedReader reads from r until n bytes were read, and then returns
+// io.EOF.
+type boundedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (b *boundedReader) Read(p []byte) (int, error) {
+	if b.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.n {
+		p = p[:b.n]
+	}
+	if r, ok := b.r.(*Reader); ok && len(p) > r.Remaining() && r.Remaining() > 0 {
+		// Read reads nothing if it cannot fill p, so read what is left
+		// first, and let the next call return ErrEntropyLimit.
+		p = p[:r.Remaining()]
+	}
+	n, err := b.r.Read(p)
+	b.n -= int64(n)
+	return n, err
+}
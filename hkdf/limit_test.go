@@ -0,0 +1,74 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestDeriveLimited(t *testing.T) {
+	secret, salt, info := []byte("secret"), []byte("salt"), []byte("info")
+	limit := MaxOutputLen(sha256.New)
+
+	for _, maxLen := range []int{0, 1, 32, 100, limit} {
+		expected := make([]byte, maxLen)
+		io.ReadFull(New(sha256.New, secret, salt, info), expected)
+
+		r, err := DeriveLimited(sha256.New, secret, salt, info, maxLen)
+		if err != nil {
+			t.Fatalf("maxLen %d: unexpected error: %v", maxLen, err)
+		}
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, r); err != nil {
+			t.Errorf("maxLen %d: unexpected error from io.Copy: %v", maxLen, err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("maxLen %d: incorrect output", maxLen)
+		}
+		if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+			t.Errorf("maxLen %d: read at end: n = %d, err = %v", maxLen, n, err)
+		}
+	}
+
+	if _, err := DeriveLimited(sha256.New, secret, salt, info, limit+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := DeriveLimited(sha256.New, secret, salt, info, -1); err == nil {
+		t.Errorf("expected an error for a negative length")
+	}
+}
+
+func TestLimitReader(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	limit := MaxOutputLen(sha256.New)
+	expected := make([]byte, limit)
+	io.ReadFull(Expand(sha256.New, prk, nil), expected)
+
+	out, err := io.ReadAll(LimitReader(Expand(sha256.New, prk, nil), 100))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(out, expected[:100]) {
+		t.Errorf("incorrect output: have %x", out)
+	}
+
+	// Reaching the entropy limit before n is an error, not io.EOF
+	out, err = io.ReadAll(LimitReader(Expand(sha256.New, prk, nil), int64(limit)+1))
+	if err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if !bytes.Equal(out, expected) {
+		t.Errorf("incorrect output before the entropy limit: %d bytes", len(out))
+	}
+
+	// Other readers are only limited
+	out, err = io.ReadAll(LimitReader(bytes.NewReader(expected), 10))
+	if err != nil || !bytes.Equal(out, expected[:10]) {
+		t.Errorf("unexpected output from a bytes.Reader: %x, %v", out, err)
+	}
+}
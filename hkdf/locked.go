@@ -0,0 +1,39 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"sync"
+)
+
+// ExtractLocked is like Extract, but returns the pseudorandom key in memory
+// that is locked with mlock, so that it is not swapped to disk, on Linux. On
+// other platforms the memory is not locked. Only the returned key is held in
+// locked memory, not the intermediate state of the HMAC computing it, nor the
+// keys later expanded from it.
+//
+// The returned unlock function overwrites the key with zeros and releases its
+// memory, after which prk must not be used. It is safe to call more than once.
+// ExtractLocked returns an error if the memory cannot be locked, for example
+// because of RLIMIT_MEMLOCK.
+func ExtractLocked(hash func() hash.Hash, secret, salt []byte) (prk []byte, unlock func(), err error) {
+	buf, release, err := allocLocked(PRKLen(hash))
+	if err != nil {
+		return nil, nil, err
+	}
+	prk = ExtractAppend(buf[:0], hash, secret, salt)
+
+	var once sync.Once
+	unlock = func() {
+		once.Do(func() {
+			for i := range buf {
+				buf[i] = 0
+			}
+			release()
+		})
+	}
+	return prk, unlock, nil
+}
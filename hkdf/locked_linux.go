@@ -0,0 +1,34 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// allocLocked returns n bytes of page-aligned memory locked with mlock, and a
+// function to unlock and unmap it.
+func allocLocked(n int) ([]byte, func(), error) {
+	pageSize := os.Getpagesize()
+	size := (n + pageSize - 1) / pageSize * pageSize
+	if size == 0 {
+		size = pageSize
+	}
+	mem, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := unix.Mlock(mem); err != nil {
+		unix.Munmap(mem)
+		return nil, nil, err
+	}
+	release := func() {
+		unix.Munlock(mem)
+		unix.Munmap(mem)
+	}
+	return mem[:n:n], release, nil
+}
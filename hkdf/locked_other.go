@@ -0,0 +1,14 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package hkdf
+
+// allocLocked returns n bytes of ordinary memory, as locking memory is only
+// supported on Linux.
+func allocLocked(n int) ([]byte, func(), error) {
+	return make([]byte, n), func() {}, nil
+}
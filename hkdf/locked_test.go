@@ -0,0 +1,27 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExtractLocked(t *testing.T) {
+	for i, tt := range hkdfTests {
+		prk, unlock, err := ExtractLocked(tt.hash, tt.master, tt.salt)
+		if err != nil {
+			t.Skipf("cannot lock memory: %v", err)
+		}
+		if !bytes.Equal(prk, tt.prk) {
+			t.Errorf("test %d: incorrect PRK: have %x, need %x", i, prk, tt.prk)
+		}
+		if len(prk) != cap(prk) {
+			t.Errorf("test %d: PRK has spare capacity %d", i, cap(prk)-len(prk))
+		}
+		unlock()
+		unlock()
+	}
+}
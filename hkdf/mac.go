@@ -0,0 +1,34 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+// NewHMAC returns an HMAC using hash, ready to authenticate arbitrary data,
+// keyed with a MAC key derived from the pseudorandom key. This is done in two
+// separate steps: first, macLen bytes are expanded from prk and info as by
+// Expand, and then the HMAC is keyed with them, as by hmac.New. It packages
+// the common pattern of deriving a MAC key and using it right away, such as
+// the finished key of a TLS 1.3 PSK binder.
+//
+// NewHMAC panics if macLen is negative or exceeds the amount of key material
+// that can be derived with hash.
+func NewHMAC(hash func() hash.Hash, prk, info []byte, macLen int) hash.Hash {
+	if macLen < 0 {
+		panic("hkdf: negative MAC key length")
+	}
+	key := make([]byte, macLen)
+	if err := ExpandInto(hash, prk, info, key); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	mac := hmac.New(hash, key)
+	for i := range key {
+		key[i] = 0
+	}
+	return mac
+}
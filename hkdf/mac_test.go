@@ -0,0 +1,94 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// recordingMAC is an HMAC that records the calls made to it.
+type recordingMAC struct {
+	hash.Hash
+	calls []string
+}
+
+func (m *recordingMAC) Reset() {
+	m.calls = append(m.calls, "Reset")
+	m.Hash.Reset()
+}
+
+func (m *recordingMAC) Write(p []byte) (int, error) {
+	m.calls = append(m.calls, fmt.Sprintf("Write(%x)", p))
+	return m.Hash.Write(p)
+}
+
+func (m *recordingMAC) Sum(b []byte) []byte {
+	m.calls = append(m.calls, "Sum")
+	return m.Hash.Sum(b)
+}
+
+func TestExpandMACCalls(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	var keys [][]byte
+	mac := &recordingMAC{}
+	r := ExpandMAC(func(key []byte) hash.Hash {
+		keys = append(keys, key)
+		mac.Hash = hmac.New(sha256.New, key)
+		return mac
+	}, prk, []byte("info"))
+
+	t1 := make([]byte, sha256.Size)
+	io.ReadFull(Expand(sha256.New, prk, []byte("info")), t1)
+	r.Read(make([]byte, sha256.Size+1))
+
+	if len(keys) != 1 || !bytes.Equal(keys[0], prk) {
+		t.Errorf("MAC keyed with %x, need a single key %x", keys, prk)
+	}
+	need := []string{
+		"Reset", "Write()", "Write(696e666f)", "Write(01)", "Sum",
+		"Reset", fmt.Sprintf("Write(%x)", t1), "Write(696e666f)", "Write(02)", "Sum",
+	}
+	if !reflect.DeepEqual(mac.calls, need) {
+		t.Errorf("have calls %q, need %q", mac.calls, need)
+	}
+}
+
+func TestNewHMAC(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("finished")
+	msg := []byte("transcript hash")
+
+	key := make([]byte, sha256.Size)
+	io.ReadFull(Expand(sha256.New, prk, info), key)
+	expected := hmac.New(sha256.New, key)
+	expected.Write(msg)
+
+	mac := NewHMAC(sha256.New, prk, info, sha256.Size)
+	mac.Write(msg)
+	if sum := mac.Sum(nil); !hmac.Equal(sum, expected.Sum(nil)) {
+		t.Errorf("have %x, need %x", sum, expected.Sum(nil))
+	}
+
+	// The HMAC can be reused like any other
+	mac.Reset()
+	mac.Write(msg)
+	if sum := mac.Sum(nil); !hmac.Equal(sum, expected.Sum(nil)) {
+		t.Errorf("after Reset: have %x, need %x", sum, expected.Sum(nil))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a MAC key over the limit")
+		}
+	}()
+	NewHMAC(sha256.New, prk, info, MaxOutputLen(sha256.New)+1)
+}
@@ -0,0 +1,53 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"io"
+)
+
+// NoiseHKDF implements the HKDF function of the Noise Protocol Framework,
+// Section 4.3. It extracts a temporary key from ikm with chainingKey as salt,
+// and returns numOutputs hash-length outputs, where each output is the HMAC of
+// the previous output followed by its one-based index. This is the same as
+// reading numOutputs blocks from Expand with an empty info.
+//
+// NoiseHKDF panics if numOutputs is not 2 or 3.
+func NoiseHKDF(hash func() hash.Hash, chainingKey, ikm []byte, numOutputs int) [][]byte {
+	if numOutputs != 2 && numOutputs != 3 {
+		panic("hkdf: Noise HKDF requires 2 or 3 outputs")
+	}
+	return chainedOutputs(hash, chainingKey, ikm, numOutputs)
+}
+
+// KDFn implements the KDF1, KDF2 and KDF3 functions of the WireGuard protocol,
+// Section 5.4. It extracts a pseudorandom key from input with key as salt, and
+// returns n hash-length outputs where t_0 is empty and each output t_i is
+// HMAC(prk, t_{i-1} || i).
+//
+// KDFn panics if n is not 1, 2 or 3.
+func KDFn(hash func() hash.Hash, key, input []byte, n int) [][]byte {
+	if n < 1 || n > 3 {
+		panic("hkdf: WireGuard KDF requires 1, 2 or 3 outputs")
+	}
+	return chainedOutputs(hash, key, input, n)
+}
+
+// chainedOutputs returns the first n blocks of Expand with an empty info,
+// using a pseudorandom key extracted from ikm with salt.
+func chainedOutputs(hash func() hash.Hash, salt, ikm []byte, n int) [][]byte {
+	prk := Extract(hash, ikm, salt)
+	r := Expand(hash, prk, nil)
+
+	outputs := make([][]byte, n)
+	for i := range outputs {
+		outputs[i] = make([]byte, len(prk))
+		if _, err := io.ReadFull(r, outputs[i]); err != nil {
+			panic("hkdf: " + err.Error())
+		}
+	}
+	return outputs
+}
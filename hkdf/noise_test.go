@@ -0,0 +1,124 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"testing"
+
+	"github.com/bored-engineer/crypto/blake2s"
+)
+
+// noiseHKDF is a direct transcription of the Noise specification.
+func noiseHKDF(h func() hash.Hash, chainingKey, ikm []byte, numOutputs int) [][]byte {
+	mac := func(key, data []byte) []byte {
+		m := hmac.New(h, key)
+		m.Write(data)
+		return m.Sum(nil)
+	}
+	tempKey := mac(chainingKey, ikm)
+	out := [][]byte{mac(tempKey, []byte{0x01})}
+	for i := 1; i < numOutputs; i++ {
+		out = append(out, mac(tempKey, append(append([]byte{}, out[i-1]...), byte(i+1))))
+	}
+	return out
+}
+
+func TestNoiseHKDF(t *testing.T) {
+	for _, h := range []func() hash.Hash{sha256.New, sha512.New} {
+		chainingKey := bytes.Repeat([]byte{0x42}, h().Size())
+		for _, ikm := range [][]byte{nil, []byte("input key material")} {
+			for _, n := range []int{2, 3} {
+				out := NoiseHKDF(h, chainingKey, ikm, n)
+				expected := noiseHKDF(h, chainingKey, ikm, n)
+				if len(out) != n {
+					t.Fatalf("have %d outputs, need %d", len(out), n)
+				}
+				for i := range out {
+					if !bytes.Equal(out[i], expected[i]) {
+						t.Errorf("%T output %d of %d: have %x, need %x", h(), i, n, out[i], expected[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNoiseHKDFOutputs(t *testing.T) {
+	for _, n := range []int{0, 1, 4} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d outputs: expected a panic", n)
+				}
+			}()
+			NoiseHKDF(sha256.New, make([]byte, sha256.Size), nil, n)
+		}()
+	}
+}
+
+func newBLAKE2s() hash.Hash {
+	h, _ := blake2s.New256(nil)
+	return h
+}
+
+// wireGuardKDFTests are the known answers of the HMAC-BLAKE2s KDF from the
+// kdf_test.go file of the wireguard-go reference implementation.
+var wireGuardKDFTests = []struct {
+	key, input []byte
+	t          [3][]byte
+}{
+	{
+		key:   []byte("test-key"),
+		input: []byte("test-input"),
+		t: [3][]byte{
+			mustDecodeHex("6f0e5ad38daba1bea8a0d213688736f19763239305e0f58aba697f9ffc41c633"),
+			mustDecodeHex("df1194df20802a4fe594cde27e92991c8cae66c366e8106aaa937a55fa371e8a"),
+			mustDecodeHex("fac6e2745a325f5dc5d11a5b165aad08b0ada28e7b4e666b7c077934a4d76c24"),
+		},
+	},
+	{
+		key:   []byte("wireguard"),
+		input: []byte("wireguard"),
+		t: [3][]byte{
+			mustDecodeHex("491d43bbfdaa8750aaf535e334ecbfe5129967cd64635101c566d4caefda96e8"),
+			mustDecodeHex("1e71a379baefd8a79aa4662212fcafe19a23e2b609a3db7d6bcba8f560e3d25f"),
+			mustDecodeHex("31e1ae48bddfbe5de38f295e5452b1909a1b4e38e183926af3780b0c1e1f0160"),
+		},
+	},
+}
+
+func TestKDFn(t *testing.T) {
+	// WireGuard instantiates HMAC with BLAKE2s-256
+	for i, tt := range wireGuardKDFTests {
+		for n := 1; n <= 3; n++ {
+			out := KDFn(newBLAKE2s, tt.key, tt.input, n)
+			if len(out) != n {
+				t.Fatalf("have %d outputs, need %d", len(out), n)
+			}
+			for j := range out {
+				if !bytes.Equal(out[j], tt.t[j]) {
+					t.Errorf("test %d: KDF%d output %d: have %x, need %x", i, n, j+1, out[j], tt.t[j])
+				}
+			}
+		}
+	}
+
+	key, input := make([]byte, blake2s.Size), []byte("wireguard input")
+	for _, n := range []int{0, 4} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d outputs: expected a panic", n)
+				}
+			}()
+			KDFn(newBLAKE2s, key, input, n)
+		}()
+	}
+}
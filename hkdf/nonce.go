@@ -0,0 +1,78 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// ErrNoncesExhausted is returned by NonceGen.Next once the limit on the
+// number of nonces has been reached.
+var ErrNoncesExhausted = errors.New("hkdf: nonce limit reached")
+
+// A NonceGen derives a sequence of deterministic nonces from a pseudorandom
+// key, and refuses to derive more than a limited number of them, so that a
+// nonce is never repeated. A NonceGen must not be used concurrently.
+//
+// The nonces only depend on the key, info and their index, so two NonceGens
+// created with the same arguments produce the same nonces. They must never
+// be used for more than one encryption key, nor recreated for the same one.
+type NonceGen struct {
+	hash     func() hash.Hash
+	prk      []byte
+	info     []byte
+	nonceLen int
+	next     uint64
+	limit    uint64
+}
+
+// NewNonceGen returns a NonceGen deriving nonceLen-byte nonces. The nonce of
+// index i is the output of Expand for prk, with info followed by i as an
+// 8-byte big-endian integer as info. By default, up to 2^64-1 nonces can be
+// derived; see SetLimit. NewNonceGen copies prk and info.
+func NewNonceGen(hash func() hash.Hash, prk, info []byte, nonceLen int) *NonceGen {
+	if nonceLen < 0 {
+		panic("hkdf: negative nonce length")
+	}
+	g := &NonceGen{hash: hash, prk: append([]byte(nil), prk...), nonceLen: nonceLen, limit: 1<<64 - 1}
+	g.info = make([]byte, len(info)+8)
+	copy(g.info, info)
+	return g
+}
+
+// SetLimit sets the maximum number of nonces g produces, counting those
+// already produced, after which Next returns ErrNoncesExhausted.
+func (g *NonceGen) SetLimit(limit uint64) {
+	g.limit = limit
+}
+
+// Next returns the next nonce. It returns ErrNoncesExhausted once the limit
+// set by SetLimit has been reached, or once the indexes would wrap around,
+// and ErrEntropyLimit if the nonces are longer than the limit for the hash.
+func (g *NonceGen) Next() ([]byte, error) {
+	if g.next >= g.limit {
+		return nil, ErrNoncesExhausted
+	}
+	binary.BigEndian.PutUint64(g.info[len(g.info)-8:], g.next)
+	nonce := make([]byte, g.nonceLen)
+	if err := ExpandInto(g.hash, g.prk, g.info, nonce); err != nil {
+		return nil, err
+	}
+	g.next++
+	return nonce, nil
+}
+
+// String returns a fixed string that does not reveal the pseudorandom key of
+// g, so that a NonceGen can be logged safely.
+func (g *NonceGen) String() string {
+	return "hkdf.NonceGen{<redacted>}"
+}
+
+// GoString is like String, and is used by the %#v verb.
+func (g *NonceGen) GoString() string {
+	return "&hkdf.NonceGen{<redacted>}"
+}
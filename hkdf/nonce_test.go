@@ -0,0 +1,69 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestNonceGen(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("nonces")
+	g := NewNonceGen(sha256.New, prk, info, 12)
+	g.SetLimit(3)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		nonce, err := g.Next()
+		if err != nil {
+			t.Fatalf("nonce %d: unexpected error: %v", i, err)
+		}
+		expected := make([]byte, 12)
+		io.ReadFull(Expand(sha256.New, prk, append([]byte("nonces"), 0, 0, 0, 0, 0, 0, 0, byte(i))), expected)
+		if !bytes.Equal(nonce, expected) {
+			t.Errorf("nonce %d: have %x, need %x", i, nonce, expected)
+		}
+		if seen[string(nonce)] {
+			t.Errorf("nonce %d: repeated nonce %x", i, nonce)
+		}
+		seen[string(nonce)] = true
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := g.Next(); err != ErrNoncesExhausted {
+			t.Errorf("have %v, need %v", err, ErrNoncesExhausted)
+		}
+	}
+	if !bytes.Equal(info, []byte("nonces")) {
+		t.Errorf("info was modified: %q", info)
+	}
+
+	// The key is copied
+	key := append([]byte(nil), prk...)
+	g = NewNonceGen(sha256.New, key, nil, 12)
+	key[0] ^= 0xff
+	nonce, _ := g.Next()
+	expected := make([]byte, 12)
+	io.ReadFull(Expand(sha256.New, prk, make([]byte, 8)), expected)
+	if !bytes.Equal(nonce, expected) {
+		t.Errorf("nonce depends on the caller's copy of the key: have %x, need %x", nonce, expected)
+	}
+
+	// The indexes never wrap around
+	g = NewNonceGen(sha256.New, prk, nil, 12)
+	g.next = 1<<64 - 2
+	if _, err := g.Next(); err != nil {
+		t.Errorf("unexpected error for the last index: %v", err)
+	}
+	if _, err := g.Next(); err != ErrNoncesExhausted {
+		t.Errorf("have %v, need %v", err, ErrNoncesExhausted)
+	}
+
+	if _, err := NewNonceGen(sha256.New, prk, nil, 255*sha256.Size+1).Next(); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+}
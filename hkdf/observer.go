@@ -0,0 +1,49 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "sync/atomic"
+
+// An Observer is notified of the derivations performed by this package, for
+// example to export metrics. It never receives any key material. Its methods
+// may be called concurrently, and must not block.
+type Observer interface {
+	// OnExtract is called for every pseudorandom key extracted, by
+	// Extract and the other extraction functions, including New and the
+	// Sum method of Extractor.
+	OnExtract()
+
+	// OnExpandBytes is called with the number of bytes of output keying
+	// material produced by each successful Read or write of WriteTo of a
+	// Reader, and by each call to ExpandInto or ExpandBlock.
+	OnExpandBytes(n int)
+}
+
+// observer holds the current Observer, or nil.
+var observer atomic.Pointer[Observer]
+
+// SetObserver sets the Observer notified by the whole package, replacing the
+// previous one. A nil Observer, the default, disables notifications, at the
+// cost of a single atomic load per operation. SetObserver is safe for
+// concurrent use.
+func SetObserver(o Observer) {
+	if o == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&o)
+}
+
+func observeExtract() {
+	if o := observer.Load(); o != nil {
+		(*o).OnExtract()
+	}
+}
+
+func observeExpand(n int) {
+	if o := observer.Load(); o != nil && n > 0 {
+		(*o).OnExpandBytes(n)
+	}
+}
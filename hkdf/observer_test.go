@@ -0,0 +1,47 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"io"
+	"sync/atomic"
+	"testing"
+)
+
+type countingObserver struct {
+	extracts, bytes atomic.Int64
+}
+
+func (c *countingObserver) OnExtract()          { c.extracts.Add(1) }
+func (c *countingObserver) OnExpandBytes(n int) { c.bytes.Add(int64(n)) }
+
+func TestSetObserver(t *testing.T) {
+	c := &countingObserver{}
+	SetObserver(c)
+	defer SetObserver(nil)
+
+	r := New(sha256.New, []byte("secret"), nil, nil)
+	io.ReadFull(r, make([]byte, 10))
+	io.ReadFull(r, make([]byte, 50))
+	Extract(sha256.New, []byte("secret"), nil)
+	ExpandInto(sha256.New, make([]byte, 32), nil, make([]byte, 40))
+	ExpandBlock(sha256.New, make([]byte, 32), nil, make([]byte, 16))
+	// Reads that fail produce no key material
+	r.Read(make([]byte, 255*sha256.Size))
+
+	if n := c.extracts.Load(); n != 2 {
+		t.Errorf("have %d extractions, need 2", n)
+	}
+	if n := c.bytes.Load(); n != 10+50+40+16 {
+		t.Errorf("have %d bytes expanded, need %d", n, 10+50+40+16)
+	}
+
+	SetObserver(nil)
+	Extract(sha256.New, []byte("secret"), nil)
+	if n := c.extracts.Load(); n != 2 {
+		t.Errorf("removed observer was notified")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"strings"
+)
+
+// openSSLDigests maps the OpenSSL names of digests, in upper case, to their
+// constructors.
+var openSSLDigests = map[string]func() hash.Hash{
+	"MD5":          md5.New,
+	"SHA1":         sha1.New,
+	"SHA-1":        sha1.New,
+	"SHA224":       sha256.New224,
+	"SHA2-224":     sha256.New224,
+	"SHA256":       sha256.New,
+	"SHA2-256":     sha256.New,
+	"SHA384":       sha512.New384,
+	"SHA2-384":     sha512.New384,
+	"SHA512":       sha512.New,
+	"SHA2-512":     sha512.New,
+	"SHA512-224":   sha512.New512_224,
+	"SHA2-512/224": sha512.New512_224,
+	"SHA512-256":   sha512.New512_256,
+	"SHA2-512/256": sha512.New512_256,
+}
+
+// OpenSSLHKDF derives length bytes like the HKDF of OpenSSL's EVP_KDF API,
+// for porting code from OpenSSL and comparing outputs with it. mdName is the
+// OpenSSL name of the digest, such as "SHA256" or "SHA2-384", and mode is one
+// of the OpenSSL modes "extract_and_expand", "extract_only" and
+// "expand_only". Both are case insensitive.
+//
+// In "extract_and_expand" mode, key is the secret, and the output is that of
+// New. In "extract_only" mode, salt and key are passed to Extract, info is
+// ignored, and length must be the output size of the digest. In "expand_only"
+// mode, key is the pseudorandom key passed to Expand, and salt is ignored.
+func OpenSSLHKDF(mdName string, mode string, key, salt, info []byte, length int) ([]byte, error) {
+	hash, ok := openSSLDigests[strings.ToUpper(mdName)]
+	if !ok {
+		return nil, errors.New("hkdf: unsupported OpenSSL digest " + mdName)
+	}
+	if length < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+
+	switch strings.ToLower(mode) {
+	case "extract_and_expand":
+		return DeriveKey(hash, key, salt, info, length)
+	case "extract_only":
+		if length != PRKLen(hash) {
+			return nil, errors.New("hkdf: extract_only output length must be the digest size")
+		}
+		return Extract(hash, key, salt), nil
+	case "expand_only":
+		out := make([]byte, length)
+		if err := ExpandInto(hash, key, info, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	default:
+		return nil, errors.New("hkdf: unsupported OpenSSL HKDF mode " + mode)
+	}
+}
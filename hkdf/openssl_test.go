@@ -0,0 +1,54 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+// openSSLTests were generated with OpenSSL 3.0, for example with
+//
+//	openssl kdf -keylen 40 -kdfopt digest:SHA256 -kdfopt mode:EXTRACT_AND_EXPAND \
+//		-kdfopt key:'input keying material' -kdfopt salt:salt -kdfopt info:context HKDF
+var openSSLTests = []struct {
+	mdName, mode string
+	out          []byte
+}{
+	{"SHA256", "EXTRACT_AND_EXPAND", mustDecodeHex("68ff61503845ceef5d49e11b1b96b7be8972f9fd319ef82dd75d2c2b54a7fbdd5027b152b682b971")},
+	{"sha384", "extract_and_expand", mustDecodeHex("f93dcdcf6fc798c0932107e69d15760ab3b08886a6edaa20bab391ad42f1fd133ee7c989037e9674f577be057586af146707cdf34c9c84f32970720acc2c2028a2bf2963c560543f8787406ea6fd69c555eb01dbc5d704c6a5841efd3d94ae918839535e")},
+	{"SHA2-256", "extract_only", mustDecodeHex("45a39914db43cbfb9a3318dd20171ca16f1f7556063ab5438d1d837f24a79bf4")},
+	{"SHA512", "extract_only", mustDecodeHex("d94518f90ae07d34d9aa69e9042e7f258ddc51ef6f70abfc043c849f4627fc15a40a0b475e04ae63ceee0afc760dd61a249aad343434bcb96db5f31154864e08")},
+	{"SHA1", "expand_only", mustDecodeHex("922752f901704edb844306af624c2a8ae76903f55286cfc9d152f5bd067e1c63e6d70e5e4946c2081ed157e6089832405fe8")},
+	{"SHA256", "expand_only", mustDecodeHex("816f7761c2afcc310ab5175bdb699e29bc083cfd33513cd90062bbea25164de1")},
+}
+
+func TestOpenSSLHKDF(t *testing.T) {
+	key, salt, info := []byte("input keying material"), []byte("salt"), []byte("context")
+	for i, tt := range openSSLTests {
+		out, err := OpenSSLHKDF(tt.mdName, tt.mode, key, salt, info, len(tt.out))
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: %s %s: have %x, need %x", i, tt.mdName, tt.mode, out, tt.out)
+		}
+	}
+
+	for _, tt := range []struct {
+		mdName, mode string
+		length       int
+	}{
+		{"SHA3-256", "extract_and_expand", 32},
+		{"SHA256", "extract_then_expand", 32},
+		{"SHA256", "extract_only", 16},
+		{"SHA256", "expand_only", 255*32 + 1},
+		{"SHA256", "extract_and_expand", -1},
+	} {
+		if _, err := OpenSSLHKDF(tt.mdName, tt.mode, key, salt, info, tt.length); err == nil {
+			t.Errorf("%s %s %d: expected an error", tt.mdName, tt.mode, tt.length)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+
+	"github.com/bored-engineer/crypto/pbkdf2"
+)
+
+// FromPassword returns a Reader from which keys can be read, derived from a
+// low-entropy password.
+//
+// HKDF is not a password hash: it is fast, so an attacker can cheaply test
+// guesses of a password used as its secret. FromPassword first stretches the
+// password with PBKDF2, using hash, salt and the given number of iterations,
+// and then uses the result as the pseudorandom key for Expand with info. The
+// salt should be random and at least 8 bytes long, and iterations should be as
+// high as is acceptable. For storing password verifiers, or where memory-hard
+// stretching is needed, use a password hash such as argon2 or scrypt instead.
+func FromPassword(hash func() hash.Hash, password, salt []byte, iterations int, info []byte) *Reader {
+	if iterations < 1 {
+		panic("hkdf: PBKDF2 requires at least one iteration")
+	}
+	prk := pbkdf2.Key(password, salt, iterations, hash().Size(), hash)
+	return Expand(hash, prk, info)
+}
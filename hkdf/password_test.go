@@ -0,0 +1,36 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"io"
+	"testing"
+)
+
+func TestFromPassword(t *testing.T) {
+	// PBKDF2-HMAC-SHA1 test vector from RFC 6070: "password", "salt", 4096.
+	prk := mustDecodeHex("4b007901b765489abead49d926f721d065a429c1")
+	info := []byte("info")
+
+	expected := make([]byte, 64)
+	io.ReadFull(Expand(sha1.New, prk, info), expected)
+
+	out := make([]byte, len(expected))
+	if _, err := io.ReadFull(FromPassword(sha1.New, []byte("password"), []byte("salt"), 4096, info), out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(out, expected) {
+		t.Errorf("have %x, need %x", out, expected)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for zero iterations")
+		}
+	}()
+	FromPassword(sha1.New, []byte("password"), []byte("salt"), 0, info)
+}
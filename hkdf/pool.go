@@ -0,0 +1,115 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"reflect"
+	"sync"
+)
+
+// macPools holds reusable HMAC states for Extract, ExpandBlock and OneKey,
+// keyed by the code pointer of the hash constructor. Only constructors that
+// capture no state are registered, so that a code pointer identifies exactly
+// one hash function.
+var macPools = make(map[uintptr]*sync.Pool)
+
+func init() {
+	for _, h := range []func() hash.Hash{
+		sha1.New,
+		sha256.New224,
+		sha256.New,
+		sha512.New384,
+		sha512.New,
+		sha512.New512_224,
+		sha512.New512_256,
+	} {
+		h := h
+		macPools[reflect.ValueOf(h).Pointer()] = &sync.Pool{
+			New: func() interface{} { return newMACState(h) },
+		}
+	}
+}
+
+// macPool returns the pool of HMAC states for hash, or nil if hash is not a
+// well-known constructor. It also returns nil when the policy of FIPSOnly is
+// enforced, so that HMAC is then only computed by crypto/hmac, within the
+// validated module.
+func macPool(hash func() hash.Hash) *sync.Pool {
+	if fipsOnly() {
+		return nil
+	}
+	return macPools[reflect.ValueOf(hash).Pointer()]
+}
+
+// macState computes HMAC (RFC 2104) over reusable hash states, so that they
+// can be borrowed from a pool instead of allocated with hmac.New.
+type macState struct {
+	inner, outer hash.Hash
+	pad          []byte
+	sum          []byte
+
+	// block holds a partial output block of ExpandBlock.
+	block []byte
+}
+
+func newMACState(h func() hash.Hash) *macState {
+	inner, outer := h(), h()
+	return &macState{
+		inner: inner,
+		outer: outer,
+		pad:   make([]byte, inner.BlockSize()),
+		sum:   make([]byte, 0, inner.Size()),
+		block: make([]byte, 0, inner.Size()),
+	}
+}
+
+// mac appends the HMAC with key of the concatenation of msg to dst, and
+// returns the resulting slice. All key dependent state is wiped before it
+// returns.
+func (m *macState) mac(dst, key []byte, msg ...[]byte) []byte {
+	if len(key) > len(m.pad) {
+		m.outer.Reset()
+		m.outer.Write(key)
+		key = m.outer.Sum(m.sum[:0])
+	}
+	copy(m.pad, key)
+	for i := range m.pad {
+		m.pad[i] ^= 0x36
+	}
+	m.inner.Reset()
+	m.inner.Write(m.pad)
+	for _, b := range msg {
+		m.inner.Write(b)
+	}
+	m.sum = m.inner.Sum(m.sum[:0])
+
+	for i := range m.pad {
+		m.pad[i] ^= 0x36 ^ 0x5c
+	}
+	m.outer.Reset()
+	m.outer.Write(m.pad)
+	m.outer.Write(m.sum)
+	dst = m.outer.Sum(dst)
+
+	m.wipe()
+	return dst
+}
+
+func (m *macState) wipe() {
+	for i := range m.pad {
+		m.pad[i] = 0
+	}
+	m.sum = m.sum[:cap(m.sum)]
+	for i := range m.sum {
+		m.sum[i] = 0
+	}
+	m.sum = m.sum[:0]
+	m.inner.Reset()
+	m.outer.Reset()
+}
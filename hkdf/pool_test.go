@@ -0,0 +1,206 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/fips140"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"testing"
+
+	"github.com/bored-engineer/crypto/sha3"
+)
+
+func TestPooledMAC(t *testing.T) {
+	if fips140.Enabled() {
+		t.Skip("pooled HMAC states are not used in FIPS 140-3 mode")
+	}
+	long := bytes.Repeat([]byte{0xaa}, 200)
+	keys := [][]byte{nil, {}, {0x01}, make([]byte, sha512.Size), long}
+	msgs := [][]byte{nil, {}, []byte("message"), long}
+
+	for _, h := range []func() hash.Hash{sha256.New, sha512.New384, sha512.New} {
+		pool := macPool(h)
+		if pool == nil {
+			t.Fatalf("no pool registered for %T", h())
+		}
+		for i, key := range keys {
+			for j, msg := range msgs {
+				mac := hmac.New(h, key)
+				mac.Write(msg)
+				expected := mac.Sum(nil)
+
+				// Compute twice to make sure no state leaks between uses
+				for k := 0; k < 2; k++ {
+					m := pool.Get().(*macState)
+					if sum := m.mac(nil, key, msg); !bytes.Equal(sum, expected) {
+						t.Errorf("%T key %d message %d: have %x, need %x", h(), i, j, sum, expected)
+					}
+					pool.Put(m)
+				}
+			}
+		}
+	}
+}
+
+func TestMACPoolClosure(t *testing.T) {
+	// Closures must never share a pool, as they may capture state
+	h := func() hash.Hash { return sha256.New() }
+	if macPool(h) != nil {
+		t.Errorf("closure unexpectedly uses a pooled HMAC")
+	}
+}
+
+func TestExtractAllocs(t *testing.T) {
+	if fips140.Enabled() || raceEnabled {
+		// HMAC states are not pooled in FIPS 140-3 mode, and the race
+		// detector drops pooled items.
+		t.Skip("HMAC states are not reliably pooled")
+	}
+	secret, salt := []byte("secret"), []byte("salt")
+	dst := make([]byte, 0, sha256.Size)
+	allocs := testing.AllocsPerRun(100, func() {
+		ExtractAppend(dst, sha256.New, secret, salt)
+	})
+	if allocs != 0 {
+		t.Errorf("ExtractAppend allocates %v times, need 0", allocs)
+	}
+}
+
+func BenchmarkExtractSHA256(b *testing.B) {
+	secret := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}
+	salt := []byte{0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Extract(sha256.New, secret, salt)
+	}
+}
+
+func TestExpandBlock(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	closure := func() hash.Hash { return sha256.New() }
+	for _, h := range []func() hash.Hash{sha256.New, closure} {
+		expected := make([]byte, sha256.Size)
+		io.ReadFull(Expand(sha256.New, prk, []byte("info")), expected)
+
+		for _, size := range []int{0, 1, 16, sha256.Size} {
+			// Compute each size twice, to check that no state leaks
+			for k := 0; k < 2; k++ {
+				out := make([]byte, size, sha256.Size)
+				if err := ExpandBlock(h, prk, []byte("info"), out); err != nil {
+					t.Fatalf("size %d: unexpected error: %v", size, err)
+				}
+				if !bytes.Equal(out, expected[:size]) {
+					t.Errorf("size %d: have %x, need %x", size, out, expected[:size])
+				}
+				if spare := out[size:cap(out)]; !bytes.Equal(spare, make([]byte, len(spare))) {
+					t.Errorf("size %d: wrote past the end of out", size)
+				}
+			}
+		}
+
+		if err := ExpandBlock(h, prk, nil, make([]byte, sha256.Size+1)); err == nil {
+			t.Errorf("expected an error for an output longer than one block")
+		}
+	}
+
+	if fips140.Enabled() || raceEnabled {
+		// HMAC states are not pooled in FIPS 140-3 mode, and the race
+		// detector drops pooled items.
+		return
+	}
+	out, info := make([]byte, sha256.Size), []byte("info")
+	allocs := testing.AllocsPerRun(100, func() {
+		ExpandBlock(sha256.New, prk, info, out)
+	})
+	if allocs != 0 {
+		t.Errorf("ExpandBlock allocates %v times, need 0", allocs)
+	}
+}
+
+func BenchmarkExpandBlockSHA256(b *testing.B) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("session key")
+	out := make([]byte, sha256.Size)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExpandBlock(sha256.New, prk, info, out)
+	}
+}
+
+func BenchmarkExtractBatchSHA256(b *testing.B) {
+	secret := []byte("master secret")
+	salts := make([][]byte, 64)
+	for i := range salts {
+		salts[i] = []byte{byte(i), 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractBatch(sha256.New, secret, salts)
+	}
+}
+
+func TestOneKey(t *testing.T) {
+	secret, salt, info := []byte("secret"), []byte("salt"), []byte("info")
+	closure := func() hash.Hash { return sha512.New() }
+	for _, h := range []func() hash.Hash{sha256.New, sha512.New, closure} {
+		for _, salt := range [][]byte{nil, salt} {
+			expected := make([]byte, h().Size())
+			io.ReadFull(New(h, secret, salt, info), expected)
+
+			// Derive twice to make sure no state leaks between uses
+			for k := 0; k < 2; k++ {
+				key, err := OneKey(h, secret, salt, info)
+				if err != nil {
+					t.Fatalf("%T: unexpected error: %v", h(), err)
+				}
+				if !bytes.Equal(key, expected) {
+					t.Errorf("%T: have %x, need %x", h(), key, expected)
+				}
+			}
+		}
+	}
+
+	if _, err := OneKey(func() hash.Hash { return shakeHash{sha3.NewShake128()} }, secret, salt, info); err == nil {
+		t.Errorf("expected an error for a hash without a fixed size")
+	}
+
+	if fips140.Enabled() || raceEnabled {
+		// HMAC states are not pooled in FIPS 140-3 mode, and the race
+		// detector drops pooled items.
+		return
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		OneKey(sha256.New, secret, salt, info)
+	})
+	if allocs != 1 {
+		t.Errorf("OneKey allocates %v times, need 1", allocs)
+	}
+}
+
+func BenchmarkOneKeySHA256(b *testing.B) {
+	secret, salt, info := []byte("master secret"), []byte("salt"), []byte("session key")
+
+	b.Run("OneKey", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			OneKey(sha256.New, secret, salt, info)
+		}
+	})
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			key := make([]byte, sha256.Size)
+			io.ReadFull(New(sha256.New, secret, salt, info), key)
+		}
+	})
+}
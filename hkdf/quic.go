@@ -0,0 +1,35 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"hash"
+)
+
+// quicV1InitialSalt is the initial salt of QUIC version 1, as defined in RFC
+// 9001, Section 5.2.
+var quicV1InitialSalt = []byte{
+	0x38, 0x76, 0x2c, 0xf7, 0xf5, 0x59, 0x34, 0xb3, 0x4d, 0x17,
+	0x9a, 0xe6, 0xa4, 0xc8, 0x0c, 0xad, 0xcc, 0xbb, 0x7f, 0x0a,
+}
+
+// QUICInitialSecrets derives the client and server Initial secrets of RFC
+// 9001, Section 5.2, from the Destination Connection ID chosen by the client.
+// The initial secret is extracted from connID with initialSalt, and each
+// secret is expanded from it with ExpandLabel.
+func QUICInitialSecrets(hash func() hash.Hash, initialSalt, connID []byte) (clientSecret, serverSecret []byte) {
+	initialSecret := Extract(hash, connID, initialSalt)
+	size := len(initialSecret)
+	clientSecret = ExpandLabel(hash, initialSecret, "client in", nil, size)
+	serverSecret = ExpandLabel(hash, initialSecret, "server in", nil, size)
+	return clientSecret, serverSecret
+}
+
+// QUICInitialSecretsV1 is like QUICInitialSecrets, using SHA-256 and the
+// initial salt of QUIC version 1.
+func QUICInitialSecretsV1(connID []byte) (clientSecret, serverSecret []byte) {
+	return QUICInitialSecrets(sha256.New, quicV1InitialSalt, connID)
+}
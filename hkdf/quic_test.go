@@ -0,0 +1,47 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+// Values from RFC 9001, Appendix A.1.
+var (
+	quicTestConnID = mustDecodeHex("8394c8f03e515708")
+
+	quicClientInitialSecret = mustDecodeHex("c00cf151ca5be075ed0ebfb5c80323c42d6b7db67881289af4008f1f6c357aea")
+	quicServerInitialSecret = mustDecodeHex("3c199828fd139efd216c155ad844cc81fb82fa8d7446fa7d78be803acdda951b")
+)
+
+func TestQUICInitialSecrets(t *testing.T) {
+	client, server := QUICInitialSecretsV1(quicTestConnID)
+	if !bytes.Equal(client, quicClientInitialSecret) {
+		t.Errorf("incorrect client secret: have %x, need %x", client, quicClientInitialSecret)
+	}
+	if !bytes.Equal(server, quicServerInitialSecret) {
+		t.Errorf("incorrect server secret: have %x, need %x", server, quicServerInitialSecret)
+	}
+
+	for _, tt := range []struct {
+		secret []byte
+		label  string
+		out    []byte
+	}{
+		{client, "quic key", mustDecodeHex("1f369613dd76d5467730efcbe3b1a22d")},
+		{client, "quic iv", mustDecodeHex("fa044b2f42a3fd3b46fb255c")},
+		{client, "quic hp", mustDecodeHex("9f50449e04a0e810283a1e9933adedd2")},
+		{server, "quic key", mustDecodeHex("cf3a5331653c364c88f0f379b6067e37")},
+		{server, "quic iv", mustDecodeHex("0ac1493ca1905853b0bba03e")},
+		{server, "quic hp", mustDecodeHex("c206b8d9b9f0f37644430b490eeaa314")},
+	} {
+		out := ExpandLabel(sha256.New, tt.secret, tt.label, nil, len(tt.out))
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("%q: have %x, need %x", tt.label, out, tt.out)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build race
+// +build race
+
+package hkdf
+
+// raceEnabled reports whether the race detector is enabled, which makes
+// sync.Pool drop items at random, so that allocations cannot be counted.
+const raceEnabled = true
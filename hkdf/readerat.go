@@ -0,0 +1,62 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+	"io"
+)
+
+// A ReaderAt provides random access to the output of Expand. Unlike Reader,
+// it holds no position, and is safe for concurrent use.
+type ReaderAt struct {
+	hash  func() hash.Hash
+	prk   []byte
+	info  []byte
+	limit int64
+}
+
+// NewReaderAt returns a ReaderAt for the output keying material that Expand
+// would produce with the same arguments. NewReaderAt copies pseudorandomKey.
+func NewReaderAt(hash func() hash.Hash, pseudorandomKey, info []byte) *ReaderAt {
+	return &ReaderAt{
+		hash:  hash,
+		prk:   append([]byte(nil), pseudorandomKey...),
+		info:  info,
+		limit: int64(MaxOutputLen(hash)),
+	}
+}
+
+// ReadAt fills p with the output keying material starting at offset off,
+// implementing io.ReaderAt. It returns ErrEntropyLimit, and reads nothing, if
+// the range extends past the entropy limit.
+//
+// Since each block depends on the previous one, every block up to the end of
+// the range is recomputed, so the cost of ReadAt grows linearly with off.
+func (r *ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("hkdf: negative offset")
+	}
+	if off > r.limit || int64(len(p)) > r.limit-off {
+		return 0, ErrEntropyLimit
+	}
+	f := Expand(r.hash, r.prk, r.info)
+	if err := f.Skip(int(off)); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(f, p)
+}
+
+// String returns a fixed string that does not reveal the pseudorandom key of
+// r, so that a ReaderAt can be logged safely.
+func (r *ReaderAt) String() string {
+	return "hkdf.ReaderAt{<redacted>}"
+}
+
+// GoString is like String, and is used by the %#v verb.
+func (r *ReaderAt) GoString() string {
+	return "&hkdf.ReaderAt{<redacted>}"
+}
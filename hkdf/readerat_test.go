@@ -0,0 +1,65 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestReaderAt(t *testing.T) {
+	prk := Extract(sha1.New, []byte("secret"), nil)
+	info := []byte("info")
+	limit := MaxOutputLen(sha1.New)
+
+	expected := make([]byte, limit)
+	io.ReadFull(Expand(sha1.New, prk, info), expected)
+
+	r := NewReaderAt(sha1.New, prk, info)
+	var wg sync.WaitGroup
+	for _, off := range []int{0, 1, 19, 20, 21, 1000, limit - 20, limit - 1, limit} {
+		for _, size := range []int{0, 1, 20, 33} {
+			if off+size > limit {
+				continue
+			}
+			wg.Add(1)
+			go func(off, size int) {
+				defer wg.Done()
+				p := make([]byte, size)
+				n, err := r.ReadAt(p, int64(off))
+				if n != size || err != nil {
+					t.Errorf("ReadAt(%d, %d): n = %d, err = %v", size, off, n, err)
+				}
+				if !bytes.Equal(p, expected[off:off+size]) {
+					t.Errorf("ReadAt(%d, %d): incorrect output", size, off)
+				}
+			}(off, size)
+		}
+	}
+	wg.Wait()
+
+	if n, err := r.ReadAt(make([]byte, 2), int64(limit-1)); n != 0 || err != ErrEntropyLimit {
+		t.Errorf("ReadAt past limit: n = %d, err = %v", n, err)
+	}
+	if n, err := r.ReadAt(make([]byte, 1), 1<<62); n != 0 || err != ErrEntropyLimit {
+		t.Errorf("ReadAt far past limit: n = %d, err = %v", n, err)
+	}
+	if _, err := r.ReadAt(make([]byte, 1), -1); err == nil {
+		t.Errorf("expected an error for a negative offset")
+	}
+
+	// The key is copied
+	key := append([]byte(nil), prk...)
+	r = NewReaderAt(sha1.New, key, info)
+	key[0] ^= 0xff
+	p := make([]byte, 20)
+	r.ReadAt(p, 0)
+	if !bytes.Equal(p, expected[:20]) {
+		t.Errorf("output depends on the caller's copy of the key: have %x, need %x", p, expected[:20])
+	}
+}
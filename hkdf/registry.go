@@ -0,0 +1,47 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"sync"
+)
+
+var (
+	hashesMu sync.RWMutex
+	hashes   = map[string]func() hash.Hash{
+		"SHA-1":   sha1.New,
+		"SHA-256": sha256.New,
+		"SHA-384": sha512.New384,
+		"SHA-512": sha512.New,
+	}
+)
+
+// RegisterHash registers h under name, so that it can be looked up by
+// HashByName, for example to restore the parameters of a derivation that
+// were stored with the name of their hash. It replaces any hash previously
+// registered under name. "SHA-1", "SHA-256", "SHA-384" and "SHA-512" are
+// registered by default. RegisterHash is safe for concurrent use, and panics
+// if h is nil.
+func RegisterHash(name string, h func() hash.Hash) {
+	if h == nil {
+		panic("hkdf: RegisterHash of nil hash constructor")
+	}
+	hashesMu.Lock()
+	defer hashesMu.Unlock()
+	hashes[name] = h
+}
+
+// HashByName returns the hash registered under name by RegisterHash, and
+// whether there is one. Names are case sensitive.
+func HashByName(name string) (func() hash.Hash, bool) {
+	hashesMu.RLock()
+	defer hashesMu.RUnlock()
+	h, ok := hashes[name]
+	return h, ok
+}
@@ -0,0 +1,58 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestHashByName(t *testing.T) {
+	for name, need := range map[string]func() hash.Hash{
+		"SHA-1":   sha1.New,
+		"SHA-256": sha256.New,
+		"SHA-384": sha512.New384,
+		"SHA-512": sha512.New,
+	} {
+		h, ok := HashByName(name)
+		if !ok || reflect.ValueOf(h).Pointer() != reflect.ValueOf(need).Pointer() {
+			t.Errorf("%s: unexpected hash %T, %v", name, h, ok)
+		}
+	}
+	if _, ok := HashByName("sha-256"); ok {
+		t.Errorf("names must be case sensitive")
+	}
+}
+
+func TestRegisterHash(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RegisterHash("test-MD5", md5.New)
+			HashByName("SHA-256")
+		}()
+	}
+	wg.Wait()
+
+	h, ok := HashByName("test-MD5")
+	if !ok || h().Size() != md5.Size {
+		t.Errorf("registered hash not found")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a nil hash")
+		}
+	}()
+	RegisterHash("nil", nil)
+}
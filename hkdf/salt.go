@@ -0,0 +1,61 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "hash"
+
+// SaltFromLabel returns a salt for Extract derived from label, the hash of
+// label, which is as long as the output of hash. It gives a deterministic
+// separation between protocols or contexts that have no random salt, so that
+// the same secret extracts to unrelated pseudorandom keys for different
+// labels.
+//
+// Such a salt is public and fixed, so it is weaker than a random salt as
+// described in RFC 5869, Section 3.1, but it is better than a nil salt for
+// domain separation.
+func SaltFromLabel(hash func() hash.Hash, label []byte) []byte {
+	h := hash()
+	h.Write(label)
+	return h.Sum(nil)
+}
+
+// NormalizeSalt returns the salt that Extract effectively uses as HMAC key
+// for salt. A nil salt is replaced by a string of zeros as long as the output
+// of hash, as specified by RFC 5869, and a salt longer than the block size of
+// hash is replaced by its hash, as specified by RFC 2104. Any other salt is
+// returned as is. Extract produces the same pseudorandom key for salt and for
+// the returned salt, which can be compared with the one of other
+// implementations.
+func NormalizeSalt(hash func() hash.Hash, salt []byte) []byte {
+	if salt == nil {
+		return defaultSalt(hash)
+	}
+	h := hash()
+	if len(salt) <= h.BlockSize() {
+		return salt
+	}
+	h.Write(salt)
+	return h.Sum(nil)
+}
+
+// IsDefaultSalt reports whether Extract uses the same HMAC key for salt as for
+// a nil salt, the string of zeros as long as the output of hash that RFC 5869
+// specifies when no salt is provided. Since HMAC pads short keys with zeros,
+// this is the case for any salt made only of zeros and no longer than the
+// block size of hash, including an empty salt. Such a salt gives no
+// randomization, so callers can use IsDefaultSalt to warn about it.
+//
+// Salts are not secret, so IsDefaultSalt does not run in constant time.
+func IsDefaultSalt(hash func() hash.Hash, salt []byte) bool {
+	if len(salt) > hash().BlockSize() {
+		return false
+	}
+	for _, b := range salt {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestSaltFromLabel(t *testing.T) {
+	salt := SaltFromLabel(sha256.New, []byte("example protocol v1"))
+	expected := sha256.Sum256([]byte("example protocol v1"))
+	if !bytes.Equal(salt, expected[:]) {
+		t.Errorf("have %x, need %x", salt, expected)
+	}
+
+	secret := []byte("secret")
+	prk := Extract(sha256.New, secret, salt)
+	if bytes.Equal(prk, Extract(sha256.New, secret, SaltFromLabel(sha256.New, []byte("example protocol v2")))) {
+		t.Errorf("different labels extracted the same PRK")
+	}
+	if bytes.Equal(prk, Extract(sha256.New, secret, nil)) {
+		t.Errorf("labeled salt extracted the same PRK as a nil salt")
+	}
+}
+
+func TestNormalizeSalt(t *testing.T) {
+	long := bytes.Repeat([]byte{0x42}, 65)
+	hashed := sha256.Sum256(long)
+	for i, tt := range []struct {
+		salt, normalized []byte
+	}{
+		{nil, make([]byte, sha256.Size)},
+		{[]byte{}, []byte{}},
+		{[]byte("salt"), []byte("salt")},
+		{long[:64], long[:64]},
+		{long, hashed[:]},
+	} {
+		salt := NormalizeSalt(sha256.New, tt.salt)
+		if !bytes.Equal(salt, tt.normalized) {
+			t.Errorf("test %d: have %x, need %x", i, salt, tt.normalized)
+		}
+		if !bytes.Equal(Extract(sha256.New, []byte("secret"), tt.salt), Extract(sha256.New, []byte("secret"), salt)) {
+			t.Errorf("test %d: normalized salt extracts a different PRK", i)
+		}
+	}
+}
+
+func TestIsDefaultSalt(t *testing.T) {
+	secret := []byte("secret")
+	prk := Extract(sha256.New, secret, nil)
+	if zeros := Extract(sha256.New, secret, make([]byte, sha256.Size)); !bytes.Equal(zeros, prk) {
+		t.Errorf("zero salt: have %x, need %x", zeros, prk)
+	}
+
+	for i, tt := range []struct {
+		salt []byte
+		ok   bool
+	}{
+		{nil, true},
+		{[]byte{}, true},
+		{make([]byte, 1), true},
+		{make([]byte, sha256.Size), true},
+		{make([]byte, sha256.BlockSize), true},
+		{make([]byte, sha256.BlockSize+1), false},
+		{[]byte("salt"), false},
+		{append(make([]byte, sha256.Size-1), 1), false},
+	} {
+		if ok := IsDefaultSalt(sha256.New, tt.salt); ok != tt.ok {
+			t.Errorf("test %d: have %v, need %v", i, ok, tt.ok)
+		}
+		if same := bytes.Equal(Extract(sha256.New, secret, tt.salt), prk); same != tt.ok {
+			t.Errorf("test %d: salt extracts the default PRK: %v, need %v", i, same, tt.ok)
+		}
+	}
+}
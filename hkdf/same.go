@@ -0,0 +1,38 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+)
+
+// SameOutput reads exactly n bytes from each of r1 and r2, and reports whether
+// they are equal, in time that does not depend on their contents. It can be
+// used to check that two derivations that must differ, for example because of
+// their salt or info, do not produce the same keys. The bytes read are
+// overwritten with zeros before SameOutput returns.
+//
+// If fewer than n bytes could be read from either reader, it returns the
+// error from io.ReadFull.
+func SameOutput(r1, r2 io.Reader, n int) (bool, error) {
+	if n < 0 {
+		return false, errors.New("hkdf: negative output length")
+	}
+	buf := make([]byte, 2*n)
+	defer func() {
+		for i := range buf {
+			buf[i] = 0
+		}
+	}()
+	if _, err := io.ReadFull(r1, buf[:n]); err != nil {
+		return false, err
+	}
+	if _, err := io.ReadFull(r2, buf[n:]); err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(buf[:n], buf[n:]) == 1, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestSameOutput(t *testing.T) {
+	secret := []byte("secret")
+	for _, tt := range []struct {
+		info1, info2 string
+		same         bool
+	}{
+		{"a", "a", true},
+		{"a", "b", false},
+	} {
+		r1 := New(sha256.New, secret, nil, []byte(tt.info1))
+		r2 := New(sha256.New, secret, nil, []byte(tt.info2))
+		same, err := SameOutput(r1, r2, 100)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if same != tt.same {
+			t.Errorf("%q and %q: have %v, need %v", tt.info1, tt.info2, same, tt.same)
+		}
+		// Exactly n bytes are read from each reader
+		if r1.Remaining() != 255*sha256.Size-100 || r2.Remaining() != 255*sha256.Size-100 {
+			t.Errorf("have %d and %d remaining", r1.Remaining(), r2.Remaining())
+		}
+	}
+
+	if _, err := SameOutput(New(sha256.New, secret, nil, nil), bytes.NewReader(make([]byte, 10)), 11); err == nil {
+		t.Errorf("expected an error for a short reader")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// DeriveSeed expands a seed of seedLen bytes from masterKey for the given
+// purpose and index, for deterministic hierarchical key generation, such as
+// seeding the index-th Ed25519 key of a purpose with ed25519.NewKeyFromSeed.
+// The master key is used as the pseudorandom key of Expand, so it must be
+// uniformly random and at least as long as the output of hash; otherwise, use
+// Extract on it first.
+//
+// The info is built by Info from purpose and index, as a 4-byte big-endian
+// integer, so that every purpose and index gives an independent seed. This is
+// not compatible with BIP 32 or SLIP-0010.
+//
+// It returns ErrEntropyLimit if seedLen exceeds the amount of key material
+// that can be derived with hash.
+func DeriveSeed(hash func() hash.Hash, masterKey []byte, purpose string, index uint32, seedLen int) ([]byte, error) {
+	if seedLen < 0 {
+		return nil, errors.New("hkdf: negative seed length")
+	}
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], index)
+	seed := make([]byte, seedLen)
+	if err := ExpandInto(hash, masterKey, Info([]byte(purpose), idx[:]), seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestDeriveSeed(t *testing.T) {
+	master := Extract(sha256.New, []byte("master secret"), nil)
+
+	seen := make(map[string]string)
+	for _, purpose := range []string{"signing", "signing\x00", "encryption", ""} {
+		for _, index := range []uint32{0, 1, 256, 0xffffffff} {
+			seed, err := DeriveSeed(sha256.New, master, purpose, index, ed25519.SeedSize)
+			if err != nil {
+				t.Fatalf("%q %d: unexpected error: %v", purpose, index, err)
+			}
+			if len(seed) != ed25519.SeedSize {
+				t.Errorf("%q %d: have length %d", purpose, index, len(seed))
+			}
+			if prev, ok := seen[string(seed)]; ok {
+				t.Errorf("%q %d: same seed as %s", purpose, index, prev)
+			}
+			seen[string(seed)] = purpose
+			ed25519.NewKeyFromSeed(seed)
+
+			again, _ := DeriveSeed(sha256.New, master, purpose, index, ed25519.SeedSize)
+			if !bytes.Equal(again, seed) {
+				t.Errorf("%q %d: seed is not deterministic", purpose, index)
+			}
+		}
+	}
+
+	if _, err := DeriveSeed(sha256.New, master, "signing", 0, MaxOutputLen(sha256.New)+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := DeriveSeed(sha256.New, master, "signing", 0, -1); err == nil {
+		t.Errorf("expected an error for a negative seed length")
+	}
+}
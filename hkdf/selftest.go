@@ -0,0 +1,122 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strconv"
+)
+
+// selfTests are the test cases of RFC 5869, Appendix A, with hex encoded
+// input keying material, salt, info and output keying material. Test cases
+// A.3 and A.6 use an empty salt, while A.7 does not provide one.
+var selfTests = []struct {
+	hash                 func() hash.Hash
+	ikm, salt, info, okm string
+	noSalt               bool // use the default salt
+}{
+	// Test case A.1
+	{
+		hash: sha256.New,
+		ikm:  "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+		salt: "000102030405060708090a0b0c",
+		info: "f0f1f2f3f4f5f6f7f8f9",
+		okm: "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf" +
+			"34007208d5b887185865",
+	},
+	// Test case A.2
+	{
+		hash: sha256.New,
+		ikm: "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" +
+			"202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f" +
+			"404142434445464748494a4b4c4d4e4f",
+		salt: "606162636465666768696a6b6c6d6e6f707172737475767778797a7b7c7d7e7f" +
+			"808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f" +
+			"a0a1a2a3a4a5a6a7a8a9aaabacadaeaf",
+		info: "b0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0c1c2c3c4c5c6c7c8c9cacbcccdcecf" +
+			"d0d1d2d3d4d5d6d7d8d9dadbdcdddedfe0e1e2e3e4e5e6e7e8e9eaebecedeeef" +
+			"f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+		okm: "b11e398dc80327a1c8e7f78c596a49344f012eda2d4efad8a050cc4c19afa97c" +
+			"59045a99cac7827271cb41c65e590e09da3275600c2f09b8367793a9aca3db71" +
+			"cc30c58179ec3e87c14c01d5c1f3434f1d87",
+	},
+	// Test case A.3
+	{
+		hash: sha256.New,
+		ikm:  "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+		okm: "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d" +
+			"9d201395faa4b61a96c8",
+	},
+	// Test case A.4
+	{
+		hash: sha1.New,
+		ikm:  "0b0b0b0b0b0b0b0b0b0b0b",
+		salt: "000102030405060708090a0b0c",
+		info: "f0f1f2f3f4f5f6f7f8f9",
+		okm: "085a01ea1b10f36933068b56efa5ad81a4f14b822f5b091568a9cdd4f155fda2" +
+			"c22e422478d305f3f896",
+	},
+	// Test case A.5
+	{
+		hash: sha1.New,
+		ikm: "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" +
+			"202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f" +
+			"404142434445464748494a4b4c4d4e4f",
+		salt: "606162636465666768696a6b6c6d6e6f707172737475767778797a7b7c7d7e7f" +
+			"808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f" +
+			"a0a1a2a3a4a5a6a7a8a9aaabacadaeaf",
+		info: "b0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0c1c2c3c4c5c6c7c8c9cacbcccdcecf" +
+			"d0d1d2d3d4d5d6d7d8d9dadbdcdddedfe0e1e2e3e4e5e6e7e8e9eaebecedeeef" +
+			"f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+		okm: "0bd770a74d1160f7c9f12cd5912a06ebff6adcae899d92191fe4305673ba2ffe" +
+			"8fa3f1a4e5ad79f3f334b3b202b2173c486ea37ce3d397ed034c7f9dfeb15c5e" +
+			"927336d0441f4c4300e2cff0d0900b52d3b4",
+	},
+	// Test case A.6
+	{
+		hash: sha1.New,
+		ikm:  "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+		okm: "0ac1af7002b3d761d1e55298da9d0506b9ae52057220a306e07b6b87e8df21d0" +
+			"ea00033de03984d34918",
+	},
+	// Test case A.7
+	{
+		hash:   sha1.New,
+		ikm:    "0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c",
+		noSalt: true,
+		okm: "2c91117204d745f3500d636a62f64f0ab3bae548aa53d423b0d1f27ebba6f5e5" +
+			"673a081d70cce7acfc48",
+	},
+}
+
+// SelfTest runs the test cases of RFC 5869, Appendix A, and returns an error
+// if any of them produces unexpected output. It is meant to be called by
+// applications that check their cryptographic primitives at startup.
+func SelfTest() error {
+	for i, tt := range selfTests {
+		ikm, _ := hex.DecodeString(tt.ikm)
+		salt, _ := hex.DecodeString(tt.salt)
+		info, _ := hex.DecodeString(tt.info)
+		okm, _ := hex.DecodeString(tt.okm)
+		if tt.noSalt {
+			salt = nil
+		}
+
+		out := make([]byte, len(okm))
+		if _, err := io.ReadFull(New(tt.hash, ikm, salt, info), out); err != nil {
+			return errors.New("hkdf: self test case A." + strconv.Itoa(i+1) + " failed: " + err.Error())
+		}
+		if !bytes.Equal(out, okm) {
+			return errors.New("hkdf: self test case A." + strconv.Itoa(i+1) + " produced incorrect output")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "testing"
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Corrupting a vector must be detected
+	saved := selfTests[2].okm
+	selfTests[2].okm = "00" + saved[2:]
+	defer func() { selfTests[2].okm = saved }()
+	if err := SelfTest(); err == nil {
+		t.Errorf("corrupted test case was not detected")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// DeriveSeq expands length bytes of key material from the pseudorandom key
+// for the sequence number seq, for rekeying schemes that derive a new key
+// for each step of a counter. The info is seq encoded as an 8-byte big-endian
+// integer, and nothing else.
+//
+// It returns ErrEntropyLimit if length exceeds the amount of key material
+// that can be derived with hash.
+func DeriveSeq(hash func() hash.Hash, prk []byte, seq uint64, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	var info [8]byte
+	binary.BigEndian.PutUint64(info[:], seq)
+	out := make([]byte, length)
+	if err := ExpandInto(hash, prk, info[:], out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
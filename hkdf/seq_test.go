@@ -0,0 +1,46 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestDeriveSeq(t *testing.T) {
+	prk := Extract(sha256.New, []byte("base secret"), nil)
+
+	expected := make([]byte, 32)
+	io.ReadFull(Expand(sha256.New, prk, []byte{0, 0, 0, 0, 0, 0, 0x01, 0x02}), expected)
+	key, err := DeriveSeq(sha256.New, prk, 0x0102, 32)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(key, expected) {
+		t.Errorf("have %x, need %x", key, expected)
+	}
+
+	// Consecutive sequence numbers give independent keys
+	seen := make(map[string]uint64)
+	for _, seq := range []uint64{0, 1, 2, 3, 1 << 32, 1<<64 - 1} {
+		key, err := DeriveSeq(sha256.New, prk, seq, 32)
+		if err != nil {
+			t.Fatalf("seq %d: unexpected error: %v", seq, err)
+		}
+		if prev, ok := seen[string(key)]; ok {
+			t.Errorf("seq %d: same key as seq %d", seq, prev)
+		}
+		seen[string(key)] = seq
+	}
+
+	if _, err := DeriveSeq(sha256.New, prk, 0, 255*sha256.Size+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+	if _, err := DeriveSeq(sha256.New, prk, 0, -1); err == nil {
+		t.Errorf("expected an error for a negative length")
+	}
+}
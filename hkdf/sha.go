@@ -0,0 +1,40 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+)
+
+// NewSHA256 is like New with sha256.New as hash.
+func NewSHA256(secret, salt, info []byte) *Reader {
+	return New(sha256.New, secret, salt, info)
+}
+
+// NewSHA384 is like New with sha512.New384 as hash.
+func NewSHA384(secret, salt, info []byte) *Reader {
+	return New(sha512.New384, secret, salt, info)
+}
+
+// NewSHA512 is like New with sha512.New as hash.
+func NewSHA512(secret, salt, info []byte) *Reader {
+	return New(sha512.New, secret, salt, info)
+}
+
+// ExtractSHA256 is like Extract with sha256.New as hash.
+func ExtractSHA256(secret, salt []byte) []byte {
+	return Extract(sha256.New, secret, salt)
+}
+
+// ExtractSHA384 is like Extract with sha512.New384 as hash.
+func ExtractSHA384(secret, salt []byte) []byte {
+	return Extract(sha512.New384, secret, salt)
+}
+
+// ExtractSHA512 is like Extract with sha512.New as hash.
+func ExtractSHA512(secret, salt []byte) []byte {
+	return Extract(sha512.New, secret, salt)
+}
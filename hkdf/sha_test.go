@@ -0,0 +1,38 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"testing"
+)
+
+func TestSHAConstructors(t *testing.T) {
+	secret, salt, info := []byte("secret"), []byte("salt"), []byte("info")
+	for _, tt := range []struct {
+		name    string
+		hash    func() hash.Hash
+		new     func(secret, salt, info []byte) *Reader
+		extract func(secret, salt []byte) []byte
+	}{
+		{"SHA-256", sha256.New, NewSHA256, ExtractSHA256},
+		{"SHA-384", sha512.New384, NewSHA384, ExtractSHA384},
+		{"SHA-512", sha512.New, NewSHA512, ExtractSHA512},
+	} {
+		if prk, need := tt.extract(secret, salt), Extract(tt.hash, secret, salt); !bytes.Equal(prk, need) {
+			t.Errorf("%s: incorrect PRK: have %x, need %x", tt.name, prk, need)
+		}
+		out, need := make([]byte, 100), make([]byte, 100)
+		io.ReadFull(tt.new(secret, salt, info), out)
+		io.ReadFull(New(tt.hash, secret, salt, info), need)
+		if !bytes.Equal(out, need) {
+			t.Errorf("%s: incorrect output: have %x, need %x", tt.name, out, need)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"io"
+)
+
+// DeriveRootKey implements the KDF_RK function of the Signal Double Ratchet
+// algorithm, Section 5.2. It derives 64 bytes from dhOutput as the secret,
+// with the current rootKey as the salt and a constant, application-specific
+// info, and splits them into a new root key and a chain key.
+func DeriveRootKey(hash func() hash.Hash, rootKey, dhOutput, info []byte) (newRoot, chainKey [32]byte) {
+	var out [64]byte
+	if _, err := io.ReadFull(New(hash, dhOutput, rootKey, info), out[:]); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	copy(newRoot[:], out[:32])
+	copy(chainKey[:], out[32:])
+	for i := range out {
+		out[i] = 0
+	}
+	return newRoot, chainKey
+}
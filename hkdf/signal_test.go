@@ -0,0 +1,55 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+// rootKeyTests are known answers of KDF_RK with SHA-256. The first is the
+// start of the output of Test Case 2 of RFC 5869, and the second uses inputs
+// shaped like those of the Signal protocol, and was computed with the hmac and
+// hashlib modules of Python.
+var rootKeyTests = []struct {
+	rootKey, dhOutput, info []byte
+	newRoot, chainKey       []byte
+}{
+	{
+		rootKey:  mustDecodeHex("606162636465666768696a6b6c6d6e6f707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9fa0a1a2a3a4a5a6a7a8a9aaabacadaeaf"),
+		dhOutput: mustDecodeHex("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f"),
+		info:     mustDecodeHex("b0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0c1c2c3c4c5c6c7c8c9cacbcccdcecfd0d1d2d3d4d5d6d7d8d9dadbdcdddedfe0e1e2e3e4e5e6e7e8e9eaebecedeeeff0f1f2f3f4f5f6f7f8f9fafbfcfdfeff"),
+		newRoot:  mustDecodeHex("b11e398dc80327a1c8e7f78c596a49344f012eda2d4efad8a050cc4c19afa97c"),
+		chainKey: mustDecodeHex("59045a99cac7827271cb41c65e590e09da3275600c2f09b8367793a9aca3db71"),
+	},
+	{
+		rootKey:  bytes.Repeat([]byte{0x01}, 32),
+		dhOutput: bytes.Repeat([]byte{0x02}, 32),
+		info:     []byte("WhisperRatchet"),
+		newRoot:  mustDecodeHex("5f8b3480a53acf984c4d253e8f836d3b3f17548503439e1688548a97ea31d236"),
+		chainKey: mustDecodeHex("71034857a2226c213eac473a6391c7bf08457662dc051d4975cc24511e20fa03"),
+	},
+}
+
+func TestDeriveRootKey(t *testing.T) {
+	for i, tt := range rootKeyTests {
+		newRoot, chainKey := DeriveRootKey(sha256.New, tt.rootKey, tt.dhOutput, tt.info)
+		if !bytes.Equal(newRoot[:], tt.newRoot) {
+			t.Errorf("test %d: incorrect root key: have %x, need %x", i, newRoot, tt.newRoot)
+		}
+		if !bytes.Equal(chainKey[:], tt.chainKey) {
+			t.Errorf("test %d: incorrect chain key: have %x, need %x", i, chainKey, tt.chainKey)
+		}
+	}
+
+	// The root key is the salt, and the DH output is the input keying material
+	tt := rootKeyTests[1]
+	newRoot, _ := DeriveRootKey(sha256.New, tt.rootKey, tt.dhOutput, tt.info)
+	swapped, _ := DeriveRootKey(sha256.New, tt.dhOutput, tt.rootKey, tt.info)
+	if swapped == newRoot {
+		t.Errorf("swapping the root key and DH output did not change the output")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+)
+
+// SP800_56C implements the two-step key derivation of NIST SP 800-56C Rev. 2,
+// Section 5, with HMAC for both steps, and returns length bytes of derived
+// keying material for the shared secret z.
+//
+// The randomness extraction step computes K_DK = HMAC-hash(salt, z), where a
+// nil salt is an all-zero string as long as the block size of hash, which is
+// equivalent as an HMAC key to the default salt of Extract. The key expansion
+// step is the KDF in feedback mode of NIST SP 800-108 Rev. 1, Section 4.2,
+// with an empty IV and an 8-bit counter placed after the fixed input data,
+// which is fixedInfo:
+//
+//	K(i) = HMAC-hash(K_DK, K(i-1) || fixedInfo || [i]_8)
+//
+// This is also HKDF-Expand, so SP800_56C returns the same output as New with
+// fixedInfo as info. The caller is responsible for encoding fixedInfo as
+// required by the key-establishment scheme, for example in the concatenation
+// format of NIST SP 800-56A Rev. 3, Section 5.8.2.1.1, which JOSEInfo
+// produces for the fields it supports.
+//
+// It returns ErrEntropyLimit if length exceeds the 255 blocks allowed by the
+// 8-bit counter.
+func SP800_56C(hash func() hash.Hash, z, salt, fixedInfo []byte, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	out := make([]byte, length)
+	if err := ExpandInto(hash, Extract(hash, z, salt), fixedInfo, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
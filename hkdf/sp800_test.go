@@ -0,0 +1,52 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestSP800_56C(t *testing.T) {
+	for i, tt := range hkdfTests {
+		out, err := SP800_56C(tt.hash, tt.master, tt.salt, tt.info, len(tt.out))
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output from SP800_56C: have %x, need %x", i, out, tt.out)
+		}
+	}
+
+	// Compute both steps directly from their definitions, with the default
+	// salt as long as the block size rather than the output size
+	z := []byte("shared secret")
+	fixedInfo := JOSEInfo("A128GCM", []byte("Alice"), []byte("Bob"), []byte{0, 0, 0, 128}, nil)
+	extract := hmac.New(sha256.New, make([]byte, sha256.BlockSize))
+	extract.Write(z)
+	kdk := extract.Sum(nil)
+	var expected, prev []byte
+	for i := byte(1); len(expected) < 40; i++ {
+		expand := hmac.New(sha256.New, kdk)
+		expand.Write(prev)
+		expand.Write(fixedInfo)
+		expand.Write([]byte{i})
+		prev = expand.Sum(nil)
+		expected = append(expected, prev...)
+	}
+	out, err := SP800_56C(sha256.New, z, nil, fixedInfo, 40)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(out, expected[:40]) {
+		t.Errorf("have %x, need %x", out, expected[:40])
+	}
+
+	if _, err := SP800_56C(sha256.New, z, nil, nil, 255*sha256.Size+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+}
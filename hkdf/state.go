@@ -0,0 +1,81 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+)
+
+// stateVersion is the first byte of the state encoded by MarshalState.
+const stateVersion = 1
+
+// MarshalState encodes the position of f in its output, so that a Reader
+// continuing at the same byte can be created by RestoreState, for example
+// after a restart. The state does not include the pseudorandom key, info,
+// context or hooks of f, which must be provided again.
+//
+// The state contains the last block of output keying material generated, so
+// it is as sensitive as the keys read from f, and must be stored encrypted.
+//
+// MarshalState returns the error of f if it has reached the entropy limit or
+// has been closed.
+func (f *Reader) MarshalState() ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	offset := len(f.prev) - len(f.buf)
+	state := make([]byte, 0, 4+len(f.prev))
+	state = append(state, stateVersion, f.counter, byte(offset>>8), byte(offset))
+	return append(state, f.prev...), nil
+}
+
+// RestoreState returns a Reader for the pseudorandom key and info positioned
+// where the Reader that returned state from MarshalState was, so that it
+// produces the rest of the same output. It returns an error if state is not
+// valid for hash.
+func RestoreState(hash func() hash.Hash, prk, info, state []byte) (*Reader, error) {
+	if len(state) < 4 || state[0] != stateVersion {
+		return nil, errors.New("hkdf: invalid Reader state")
+	}
+	r := Expand(hash, prk, info)
+	counter := state[1]
+	offset := int(state[2])<<8 | int(state[3])
+	prev := state[4:]
+	switch {
+	case counter == 1 && len(prev) != 0,
+		counter != 1 && len(prev) != r.size,
+		offset > len(prev):
+		return nil, errors.New("hkdf: invalid Reader state")
+	}
+	r.counter = counter
+	r.prev = append(r.prev, prev...)
+	r.buf = r.prev[offset:]
+	return r, nil
+}
+
+// ExpandFrom returns a Reader for the pseudorandom key and info that starts at
+// block T(counter), given the previous block prevBlock, T(counter-1), so that
+// callers tracking the last block generated can resume the output at any
+// block boundary without the byte offset kept by MarshalState. The first
+// block has counter 1 and an empty prevBlock.
+//
+// Like the state of MarshalState, prevBlock is output keying material. It
+// returns an error if counter is 0, or if prevBlock is not empty for counter 1,
+// or is not as long as the output of hash for any other counter.
+func ExpandFrom(hash func() hash.Hash, prk, info, prevBlock []byte, counter byte) (*Reader, error) {
+	r := Expand(hash, prk, info)
+	switch {
+	case counter == 0:
+		return nil, errors.New("hkdf: invalid block counter")
+	case counter == 1 && len(prevBlock) != 0,
+		counter != 1 && len(prevBlock) != r.size:
+		return nil, errors.New("hkdf: invalid previous block length")
+	}
+	r.counter = counter
+	r.prev = append(r.prev, prevBlock...)
+	r.buf = r.prev[len(r.prev):]
+	return r, nil
+}
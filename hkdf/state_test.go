@@ -0,0 +1,115 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"testing"
+)
+
+func TestMarshalState(t *testing.T) {
+	for _, h := range []func() hash.Hash{sha256.New, sha512.New} {
+		size := h().Size()
+		prk := Extract(h, []byte("secret"), nil)
+		limit := 255 * size
+		expected := make([]byte, limit)
+		io.ReadFull(Expand(h, prk, []byte("info")), expected)
+
+		for _, pos := range []int{0, 1, size - 1, size, size + 1, 100, limit - size, limit - 1, limit} {
+			r := Expand(h, prk, []byte("info"))
+			io.ReadFull(r, make([]byte, pos))
+			state, err := r.MarshalState()
+			if err != nil {
+				t.Fatalf("%T: position %d: unexpected error: %v", h(), pos, err)
+			}
+
+			restored, err := RestoreState(h, prk, []byte("info"), state)
+			if err != nil {
+				t.Fatalf("%T: position %d: unexpected error: %v", h(), pos, err)
+			}
+			if have, need := restored.Remaining(), r.Remaining(); have != need {
+				t.Errorf("%T: position %d: have %d remaining, need %d", h(), pos, have, need)
+			}
+			rest := make([]byte, limit-pos)
+			if _, err := io.ReadFull(restored, rest); err != nil {
+				t.Fatalf("%T: position %d: unexpected error: %v", h(), pos, err)
+			}
+			if !bytes.Equal(rest, expected[pos:]) {
+				t.Errorf("%T: position %d: restored output differs", h(), pos)
+			}
+			if _, err := restored.Read(make([]byte, 1)); err != ErrEntropyLimit {
+				t.Errorf("%T: position %d: have %v at the limit, need %v", h(), pos, err, ErrEntropyLimit)
+			}
+		}
+	}
+
+	r := New(sha256.New, []byte("secret"), nil, nil)
+	r.Close()
+	if _, err := r.MarshalState(); err != ErrClosed {
+		t.Errorf("have %v, need %v", err, ErrClosed)
+	}
+
+	prk := make([]byte, sha256.Size)
+	for _, state := range [][]byte{
+		nil,
+		{2, 1, 0, 0},
+		{1, 1, 0, 0, 0xff},
+		{1, 2, 0, 0},
+		append([]byte{1, 2, 0, 33}, make([]byte, 32)...),
+	} {
+		if _, err := RestoreState(sha256.New, prk, nil, state); err == nil {
+			t.Errorf("state %x: expected an error", state)
+		}
+	}
+}
+
+func TestExpandFrom(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	limit := MaxOutputLen(sha256.New)
+	expected := make([]byte, limit)
+	io.ReadFull(Expand(sha256.New, prk, []byte("info")), expected)
+
+	for _, counter := range []int{1, 2, 100, 255} {
+		var prev []byte
+		if counter > 1 {
+			prev = expected[(counter-2)*sha256.Size : (counter-1)*sha256.Size]
+		}
+		r, err := ExpandFrom(sha256.New, prk, []byte("info"), prev, byte(counter))
+		if err != nil {
+			t.Fatalf("counter %d: unexpected error: %v", counter, err)
+		}
+		rest, err := io.ReadAll(LimitReader(r, int64(r.Remaining())))
+		if err != nil {
+			t.Errorf("counter %d: unexpected error: %v", counter, err)
+		}
+		if !bytes.Equal(rest, expected[(counter-1)*sha256.Size:]) {
+			t.Errorf("counter %d: incorrect output", counter)
+		}
+		if _, err := r.Read(make([]byte, 1)); err != ErrEntropyLimit {
+			t.Errorf("counter %d: have %v at the end, need %v", counter, err, ErrEntropyLimit)
+		}
+	}
+
+	block := expected[:sha256.Size]
+	for _, tt := range []struct {
+		prev    []byte
+		counter byte
+	}{
+		{nil, 0},
+		{block, 0},
+		{block, 1},
+		{nil, 2},
+		{block[:31], 2},
+		{append(block, 0), 2},
+	} {
+		if _, err := ExpandFrom(sha256.New, prk, []byte("info"), tt.prev, tt.counter); err == nil {
+			t.Errorf("counter %d, previous block of %d bytes: expected an error", tt.counter, len(tt.prev))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	stdhkdf "crypto/hkdf"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"testing"
+)
+
+// fuzzHashes are the hashes selected by the fuzzer. They include both hashes
+// with a pooled Extract fast path and one without.
+var fuzzHashes = []func() hash.Hash{
+	sha256.New,
+	sha1.New,
+	sha512.New,
+	sha512.New384,
+	md5.New,
+	func() hash.Hash { return sha256.New() },
+}
+
+// FuzzHKDFMatchesStdlib checks that New produces the same output as the
+// crypto/hkdf package of the standard library.
+func FuzzHKDFMatchesStdlib(f *testing.F) {
+	for _, tt := range hkdfTests {
+		f.Add(uint8(0), tt.master, tt.salt, tt.info, uint16(len(tt.out)))
+	}
+	f.Add(uint8(4), []byte("secret"), []byte(nil), []byte("info"), uint16(255*16))
+
+	f.Fuzz(func(t *testing.T, h uint8, secret, salt, info []byte, length uint16) {
+		hash := fuzzHashes[int(h)%len(fuzzHashes)]
+		if int(length) > MaxOutputLen(hash) {
+			t.Skip("length beyond the entropy limit")
+		}
+
+		out := make([]byte, length)
+		if _, err := io.ReadFull(New(hash, secret, salt, info), out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := stdhkdf.Key(hash, secret, salt, string(info), int(length))
+		if err != nil {
+			t.Fatalf("unexpected error from crypto/hkdf: %v", err)
+		}
+		if !bytes.Equal(out, expected) {
+			t.Errorf("have %x, need %x", out, expected)
+		}
+	})
+}
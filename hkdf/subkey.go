@@ -0,0 +1,26 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"io"
+)
+
+// DeriveSubkey derives a 32-byte subkey from a master key and a context
+// string, by using key as the pseudorandom key of Expand and context as its
+// info. The master key must be uniformly random, and at least as long as the
+// output of hash; otherwise, use New to extract from it first.
+//
+// DeriveSubkey is not compatible with HChaCha20, which is used to extend the
+// nonce of XChaCha20. Use NewX from
+// github.com/bored-engineer/crypto/chacha20poly1305 for that.
+func DeriveSubkey(hash func() hash.Hash, key, context []byte) [32]byte {
+	var subkey [32]byte
+	if _, err := io.ReadFull(Expand(hash, key, context), subkey[:]); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	return subkey
+}
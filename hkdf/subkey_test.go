@@ -0,0 +1,34 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestDeriveSubkey(t *testing.T) {
+	key := bytes.Repeat([]byte{0x5a}, 32)
+
+	expected := make([]byte, 32)
+	io.ReadFull(Expand(sha256.New, key, []byte("file encryption")), expected)
+	subkey := DeriveSubkey(sha256.New, key, []byte("file encryption"))
+	if !bytes.Equal(subkey[:], expected) {
+		t.Errorf("have %x, need %x", subkey, expected)
+	}
+
+	if DeriveSubkey(sha256.New, key, []byte("file authentication")) == subkey {
+		t.Errorf("different contexts produced the same subkey")
+	}
+
+	// Hashes shorter than the subkey use more than one block
+	io.ReadFull(Expand(sha1.New, key, nil), expected)
+	if subkey := DeriveSubkey(sha1.New, key, nil); !bytes.Equal(subkey[:], expected) {
+		t.Errorf("SHA-1: have %x, need %x", subkey, expected)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"io"
+	"sync"
+)
+
+// Synchronized returns a reader that serializes calls to the Read method of r,
+// such as a Reader, so that it can be shared by multiple goroutines. Each Read
+// returns a contiguous range of the output of r, disjoint from the ranges
+// returned to other callers. The output is still computed one Read at a time.
+func Synchronized(r io.Reader) io.Reader {
+	return &syncReader{r: r}
+}
+
+type syncReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (s *syncReader) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Read(p)
+}
@@ -0,0 +1,55 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestSynchronized(t *testing.T) {
+	const chunk = 17
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	limit := MaxOutputLen(sha256.New)
+	chunks := limit / chunk
+
+	expected := make([]byte, limit)
+	io.ReadFull(Expand(sha256.New, prk, nil), expected)
+
+	r := Synchronized(Expand(sha256.New, prk, nil))
+	results := make(chan []byte, chunks)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				p := make([]byte, chunk)
+				if _, err := r.Read(p); err != nil {
+					return
+				}
+				results <- p
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	// Every chunk must be a distinct, aligned range of the output
+	seen := make(map[int]bool)
+	for p := range results {
+		i := bytes.Index(expected, p)
+		if i < 0 || i%chunk != 0 || seen[i] {
+			t.Fatalf("chunk %x is not a distinct range of the output", p)
+		}
+		seen[i] = true
+	}
+	if len(seen) != chunks {
+		t.Errorf("have %d chunks, need %d", len(seen), chunks)
+	}
+}
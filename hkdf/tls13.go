@@ -0,0 +1,65 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"io"
+
+	"github.com/bored-engineer/crypto/cryptobyte"
+)
+
+// tls13LabelPrefix is prepended to every HKDF-Expand-Label label by TLS 1.3.
+const tls13LabelPrefix = "tls13 "
+
+// ExpandLabel implements HKDF-Expand-Label from RFC 8446, Section 7.1. It
+// expands length bytes from secret using the HkdfLabel structure built from
+// length, "tls13 " followed by label, and context.
+//
+// ExpandLabel panics if the prefixed label or context are longer than 255
+// bytes, or if length does not fit in a uint16.
+func ExpandLabel(hash func() hash.Hash, secret []byte, label string, context []byte, length int) []byte {
+	return ExpandLabelPrefix(hash, secret, tls13LabelPrefix, label, context, length)
+}
+
+// ExpandLabelPrefix is like ExpandLabel, but prepends prefix to label instead
+// of "tls13 ", for protocols that reuse the HkdfLabel structure with their own
+// prefix, such as "dtls13" for DTLS 1.3.
+func ExpandLabelPrefix(hash func() hash.Hash, secret []byte, prefix, label string, context []byte, length int) []byte {
+	if len(prefix)+len(label) > 255 {
+		panic("hkdf: HKDF-Expand-Label label is longer than 255 bytes")
+	}
+	if length < 0 || length > 0xffff {
+		panic("hkdf: invalid HKDF-Expand-Label output length")
+	}
+	var hkdfLabel cryptobyte.Builder
+	hkdfLabel.AddUint16(uint16(length))
+	hkdfLabel.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes([]byte(prefix))
+		b.AddBytes([]byte(label))
+	})
+	hkdfLabel.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(context)
+	})
+	info, err := hkdfLabel.Bytes()
+	if err != nil {
+		panic("hkdf: HKDF-Expand-Label context is longer than 255 bytes")
+	}
+
+	out := make([]byte, length)
+	if _, err := io.ReadFull(Expand(hash, secret, info), out); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	return out
+}
+
+// DeriveSecret implements Derive-Secret from RFC 8446, Section 7.1. It hashes
+// transcript, the concatenation of the handshake messages, with hash and
+// expands a hash-length secret with that digest as the ExpandLabel context.
+func DeriveSecret(hash func() hash.Hash, secret []byte, label string, transcript []byte) []byte {
+	h := hash()
+	h.Write(transcript)
+	return ExpandLabel(hash, secret, label, h.Sum(nil), h.Size())
+}
@@ -0,0 +1,109 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Values from the simple 1-RTT handshake of RFC 8448, Section 3.
+var (
+	tls13EarlySecret = mustDecodeHex("33ad0a1c607ec03b09e6cd9893680ce210adf300aa1f2660e1b22e10f170f92a")
+	// Derive-Secret(early secret, "derived", "").
+	tls13DerivedSecret = mustDecodeHex("6f2615a108c702c5678f54fc9dbab69716c076189c48250cebeac3576c3611ba")
+
+	tls13HandshakeSecret = mustDecodeHex("1dc826e93606aa6fdc0aadc12f741b01046aa6b99f691ed221a9f0ca043fbeac")
+	// Transcript hash of ClientHello...ServerHello.
+	tls13HelloHash = mustDecodeHex("860c06edc07858ee8e78f0e7428c58edd6b43f2ca3e6e95f02ed063cf0e1cad8")
+
+	tls13ClientHandshakeTrafficSecret = mustDecodeHex("b3eddb126e067f35a780b3abf45e2d8f3b1a950738f52e9600746a0e27a55a21")
+	tls13ServerHandshakeTrafficSecret = mustDecodeHex("b67b7d690cc16c4e75e54213cb2d37b4e9c912bcded9105d42befd59d391ad38")
+)
+
+func TestExpandLabel(t *testing.T) {
+	for _, tt := range []struct {
+		secret  []byte
+		label   string
+		context []byte
+		out     []byte
+	}{
+		{tls13HandshakeSecret, "c hs traffic", tls13HelloHash, tls13ClientHandshakeTrafficSecret},
+		{tls13HandshakeSecret, "s hs traffic", tls13HelloHash, tls13ServerHandshakeTrafficSecret},
+		{tls13ClientHandshakeTrafficSecret, "key", nil, mustDecodeHex("dbfaa693d1762c5b666af5d950258d01")},
+		{tls13ClientHandshakeTrafficSecret, "iv", nil, mustDecodeHex("5bd3c71b836e0b76bb73265f")},
+		{tls13ServerHandshakeTrafficSecret, "key", nil, mustDecodeHex("3fce516009c21727d0f2e4e86ee403bc")},
+		{tls13ServerHandshakeTrafficSecret, "iv", nil, mustDecodeHex("5d313eb2671276ee13000b30")},
+	} {
+		out := ExpandLabel(sha256.New, tt.secret, tt.label, tt.context, len(tt.out))
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("%q: have %x, need %x", tt.label, out, tt.out)
+		}
+	}
+}
+
+func TestExpandLabelTooLong(t *testing.T) {
+	secret := make([]byte, sha256.Size)
+
+	// The longest permitted label should not panic
+	ExpandLabel(sha256.New, secret, strings.Repeat("a", 249), nil, 16)
+
+	for name, f := range map[string]func(){
+		"label":   func() { ExpandLabel(sha256.New, secret, strings.Repeat("a", 250), nil, 16) },
+		"context": func() { ExpandLabel(sha256.New, secret, "key", make([]byte, 256), 16) },
+		"length":  func() { ExpandLabel(sha256.New, secret, "key", nil, 0x10000) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestDeriveSecret(t *testing.T) {
+	out := DeriveSecret(sha256.New, tls13EarlySecret, "derived", nil)
+	if !bytes.Equal(out, tls13DerivedSecret) {
+		t.Errorf("have %x, need %x", out, tls13DerivedSecret)
+	}
+
+	// The handshake secret is extracted with the derived secret as salt from
+	// the (EC)DHE shared secret.
+	shared := mustDecodeHex("8bd4054fb55b9d63fdfbacf9f04b9f0d35e6d63f537563efd46272900f89492d")
+	if hs := Extract(sha256.New, shared, out); !bytes.Equal(hs, tls13HandshakeSecret) {
+		t.Errorf("incorrect handshake secret: have %x, need %x", hs, tls13HandshakeSecret)
+	}
+}
+
+func TestExpandLabelPrefix(t *testing.T) {
+	secret := tls13ClientHandshakeTrafficSecret
+
+	out := ExpandLabelPrefix(sha256.New, secret, "tls13 ", "key", nil, 16)
+	if expected := ExpandLabel(sha256.New, secret, "key", nil, 16); !bytes.Equal(out, expected) {
+		t.Errorf("tls13 prefix: have %x, need %x", out, expected)
+	}
+
+	// HkdfLabel for DTLS 1.3: length 16, "dtls13" + "key", empty context
+	info := append([]byte{0x00, 0x10, 9}, "dtls13key"...)
+	info = append(info, 0)
+	expected := make([]byte, 16)
+	io.ReadFull(Expand(sha256.New, secret, info), expected)
+	if out := ExpandLabelPrefix(sha256.New, secret, "dtls13", "key", nil, 16); !bytes.Equal(out, expected) {
+		t.Errorf("dtls13 prefix: have %x, need %x", out, expected)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a prefixed label over 255 bytes")
+		}
+	}()
+	ExpandLabelPrefix(sha256.New, secret, "dtls13", strings.Repeat("a", 250), nil, 16)
+}
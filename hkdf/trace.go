@@ -0,0 +1,55 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import "hash"
+
+// ExpandTraced is like Expand, but calls trace with the exact input of the
+// HMAC computing each block T(i), that is T(i-1) || info || i, before the block
+// is returned. The input is a copy, which trace may retain.
+//
+// ExpandTraced is only meant for debugging interoperability with other HKDF
+// implementations. The input of every block after the first contains the
+// previous block, which is output keying material, so trace must not log it
+// anywhere secrets are not allowed.
+func ExpandTraced(hash func() hash.Hash, prk, info []byte, trace func(block byte, hmacInput []byte)) *Reader {
+	return newReader(tracedHMAC(hash, prk, trace), info)
+}
+
+// tracedHMAC is like keyedHMAC, but the returned HMACs pass their input to
+// trace.
+func tracedHMAC(h func() hash.Hash, key []byte, trace func(block byte, hmacInput []byte)) func() hash.Hash {
+	return func() hash.Hash {
+		return &tracingHash{Hash: newHMAC(h, key), trace: trace}
+	}
+}
+
+// tracingHash records the input written to Hash since its last Reset, and
+// passes a copy of it to trace on every call to Sum.
+type tracingHash struct {
+	hash.Hash
+	trace func(block byte, hmacInput []byte)
+	input []byte
+}
+
+func (h *tracingHash) Write(p []byte) (int, error) {
+	h.input = append(h.input, p...)
+	return h.Hash.Write(p)
+}
+
+func (h *tracingHash) Sum(b []byte) []byte {
+	if len(h.input) > 0 {
+		h.trace(h.input[len(h.input)-1], append([]byte(nil), h.input...))
+	}
+	return h.Hash.Sum(b)
+}
+
+func (h *tracingHash) Reset() {
+	for i := range h.input {
+		h.input[i] = 0
+	}
+	h.input = h.input[:0]
+	h.Hash.Reset()
+}
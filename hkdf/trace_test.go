@@ -0,0 +1,53 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestExpandTraced(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	info := []byte("info")
+	expected := make([]byte, 100)
+	io.ReadFull(Expand(sha256.New, prk, info), expected)
+
+	var blocks []byte
+	var inputs [][]byte
+	r := ExpandTraced(sha256.New, prk, info, func(block byte, hmacInput []byte) {
+		blocks = append(blocks, block)
+		inputs = append(inputs, hmacInput)
+	})
+	out := make([]byte, len(expected))
+	io.ReadFull(r, out)
+	if !bytes.Equal(out, expected) {
+		t.Errorf("incorrect output: have %x, need %x", out, expected)
+	}
+	if !bytes.Equal(blocks, []byte{1, 2, 3, 4}) {
+		t.Fatalf("traced blocks %v, need [1 2 3 4]", blocks)
+	}
+
+	var prev []byte
+	for i, input := range inputs {
+		need := append(append(append([]byte(nil), prev...), info...), byte(i+1))
+		if !bytes.Equal(input, need) {
+			t.Errorf("block %d: have input %x, need %x", i+1, input, need)
+		}
+		mac := hmac.New(sha256.New, prk)
+		mac.Write(input)
+		prev = mac.Sum(nil)
+	}
+
+	// Each trace owns its input, even as the Reader keeps going
+	first := append([]byte(nil), inputs[0]...)
+	io.ReadFull(r, make([]byte, 100))
+	if !bytes.Equal(inputs[0], first) {
+		t.Errorf("traced input was modified after the call")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// Unmarshal fills the fields of the struct pointed to by dst with bytes read
+// from r, in declaration order, for example to split the output of a Reader
+// into the keys of a key schedule:
+//
+//	var keys struct {
+//		EncKey [32]byte
+//		MACKey []byte `hkdf:"32"`
+//		IV     [12]byte
+//	}
+//	err := hkdf.Unmarshal(hkdf.New(sha256.New, secret, salt, info), &keys)
+//
+// Fields must be exported, and be byte arrays or byte slices. A byte slice
+// field is set to a new slice whose length is given by its "hkdf" tag, which
+// is required. A byte array field is filled entirely, and if it has an "hkdf"
+// tag, the tag must match its length. Fields tagged with "hkdf:\"-\"" are
+// skipped.
+//
+// Unmarshal returns an error if dst is not a pointer to a struct, if a field
+// is not supported, or if r returns an error before all the fields are
+// filled, in which case the fields may have been partially filled.
+func Unmarshal(r io.Reader, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("hkdf: Unmarshal destination must be a non-nil pointer to a struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("hkdf")
+		if tag == "-" {
+			continue
+		}
+		if field.PkgPath != "" {
+			return errors.New("hkdf: Unmarshal of unexported field " + field.Name)
+		}
+		length := -1
+		if tagged {
+			n, err := strconv.Atoi(tag)
+			if err != nil || n < 0 {
+				return errors.New("hkdf: invalid length tag for field " + field.Name)
+			}
+			length = n
+		}
+
+		var buf []byte
+		fv := v.Field(i)
+		switch {
+		case fv.Kind() == reflect.Array && field.Type.Elem().Kind() == reflect.Uint8:
+			if tagged && length != fv.Len() {
+				return errors.New("hkdf: length tag does not match the size of field " + field.Name)
+			}
+			buf = fv.Slice(0, fv.Len()).Bytes()
+		case fv.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Uint8:
+			if !tagged {
+				return errors.New("hkdf: missing length tag for byte slice field " + field.Name)
+			}
+			buf = make([]byte, length)
+			fv.SetBytes(buf)
+		default:
+			return errors.New("hkdf: Unmarshal of unsupported type " + field.Type.String() + " for field " + field.Name)
+		}
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	secret, info := []byte("secret"), []byte("key schedule")
+	expected := make([]byte, 32+20+12)
+	io.ReadFull(New(sha256.New, secret, nil, info), expected)
+
+	var keys struct {
+		EncKey  [32]byte
+		MACKey  []byte   `hkdf:"20"`
+		Skipped string   `hkdf:"-"`
+		IV      [12]byte `hkdf:"12"`
+	}
+	if err := Unmarshal(New(sha256.New, secret, nil, info), &keys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(keys.EncKey[:], expected[:32]) {
+		t.Errorf("incorrect EncKey: have %x, need %x", keys.EncKey, expected[:32])
+	}
+	if !bytes.Equal(keys.MACKey, expected[32:52]) {
+		t.Errorf("incorrect MACKey: have %x, need %x", keys.MACKey, expected[32:52])
+	}
+	if !bytes.Equal(keys.IV[:], expected[52:]) {
+		t.Errorf("incorrect IV: have %x, need %x", keys.IV, expected[52:])
+	}
+
+	for name, dst := range map[string]interface{}{
+		"non-pointer":    struct{ Key [16]byte }{},
+		"nil pointer":    (*struct{ Key [16]byte })(nil),
+		"non-struct":     new([16]byte),
+		"untagged slice": &struct{ Key []byte }{},
+		"invalid tag": &struct {
+			Key []byte `hkdf:"x"`
+		}{},
+		"mismatched tag": &struct {
+			Key [16]byte `hkdf:"32"`
+		}{},
+		"unsupported":   &struct{ Key string }{},
+		"integer array": &struct{ Key [4]int }{},
+		"unexported":    &struct{ key [16]byte }{},
+		"past the limit": &struct {
+			Key []byte `hkdf:"8161"`
+		}{},
+	} {
+		if err := Unmarshal(New(sha256.New, secret, nil, info), dst); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
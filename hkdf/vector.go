@@ -0,0 +1,28 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// Vector returns the first length bytes that New produces for the given
+// hash, secret, salt and info, hex encoded. It is intended for golden tests
+// that pin the exact output of a derivation.
+//
+// Vector panics if length is negative or exceeds the amount of key material
+// that can be derived with hash.
+func Vector(hash func() hash.Hash, secret, salt, info []byte, length int) string {
+	if length < 0 || length > MaxOutputLen(hash) {
+		panic("hkdf: Vector length out of range; at most 255 times the output size of hash can be derived")
+	}
+	out := make([]byte, length)
+	if _, err := io.ReadFull(New(hash, secret, salt, info), out); err != nil {
+		panic("hkdf: " + err.Error())
+	}
+	return hex.EncodeToString(out)
+}
@@ -0,0 +1,33 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVector(t *testing.T) {
+	for i, tt := range hkdfTests {
+		if v, need := Vector(tt.hash, tt.master, tt.salt, tt.info, len(tt.out)), hex.EncodeToString(tt.out); v != need {
+			t.Errorf("test %d: have %s, need %s", i, v, need)
+		}
+	}
+	if v := Vector(sha256.New, nil, nil, nil, 0); v != "" {
+		t.Errorf("have %q for an empty output", v)
+	}
+
+	for _, length := range []int{-1, 255*sha256.Size + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("length %d: expected a panic", length)
+				}
+			}()
+			Vector(sha256.New, nil, nil, nil, length)
+		}()
+	}
+}
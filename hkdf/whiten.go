@@ -0,0 +1,38 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/bored-engineer/crypto/sha3"
+)
+
+// ExpandWhitened expands length bytes of key material from the pseudorandom
+// key and optional context info, like Expand, and returns the first length
+// bytes of the SHAKE256 output for them, so that the keys do not have the
+// structure of HMAC outputs.
+//
+// This whitening step is not part of RFC 5869, and is not needed for the
+// security of HKDF. It is only meant for threat models that want an
+// additional, independent primitive after the derivation. The output is
+// incompatible with that of Expand.
+//
+// It returns ErrEntropyLimit if length exceeds the amount of key material
+// that can be derived with hash.
+func ExpandWhitened(hash func() hash.Hash, prk, info []byte, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, errors.New("hkdf: negative output length")
+	}
+	out := make([]byte, length)
+	if err := ExpandInto(hash, prk, info, out); err != nil {
+		return nil, err
+	}
+	shake := sha3.NewShake256()
+	shake.Write(out)
+	shake.Read(out)
+	return out, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/bored-engineer/crypto/sha3"
+)
+
+func TestExpandWhitened(t *testing.T) {
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	for _, length := range []int{0, 16, 32, 100} {
+		raw := make([]byte, length)
+		io.ReadFull(Expand(sha256.New, prk, []byte("info")), raw)
+		expected := make([]byte, length)
+		sha3.ShakeSum256(expected, raw)
+
+		out, err := ExpandWhitened(sha256.New, prk, []byte("info"), length)
+		if err != nil {
+			t.Fatalf("length %d: unexpected error: %v", length, err)
+		}
+		if !bytes.Equal(out, expected) {
+			t.Errorf("length %d: have %x, need %x", length, out, expected)
+		}
+		if length > 0 && bytes.Equal(out, raw) {
+			t.Errorf("length %d: output was not whitened", length)
+		}
+	}
+
+	if _, err := ExpandWhitened(sha256.New, prk, nil, 255*sha256.Size+1); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+}
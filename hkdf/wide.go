@@ -0,0 +1,83 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+	"io"
+)
+
+// ExpandWide is like Expand, but encodes the block counter on counterBytes
+// bytes, in big-endian order. With a counterBytes of 1 it returns a Reader
+// identical to Expand. With a counterBytes of 2, up to 65535 blocks can be
+// read instead of 255, but the output is not defined by RFC 5869, and is
+// incompatible with standard HKDF, even for the first block.
+//
+// The returned reader returns ErrEntropyLimit, and reads nothing, if fewer
+// than len(p) bytes remain. ExpandWide panics if counterBytes is not 1 or 2.
+func ExpandWide(hash func() hash.Hash, prk, info []byte, counterBytes int) io.Reader {
+	switch counterBytes {
+	case 1:
+		return Expand(hash, prk, info)
+	case 2:
+		expander := newHMAC(hash, prk)
+		return &wideReader{expander: expander, info: info, counter: 1}
+	default:
+		panic("hkdf: counter width must be 1 or 2 bytes")
+	}
+}
+
+// wideReader is a Reader with a 16-bit block counter.
+type wideReader struct {
+	expander hash.Hash
+	info     []byte
+	counter  uint32
+
+	// ctr holds the encoded counter, so that it is not allocated per block.
+	ctr [2]byte
+
+	prev []byte
+	buf  []byte
+	err  error
+}
+
+func (f *wideReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if f.err != nil {
+		return 0, f.err
+	}
+	remaining := len(f.buf) + int(0xffff+1-f.counter)*f.expander.Size()
+	if remaining < len(p) {
+		f.err = ErrEntropyLimit
+		return 0, f.err
+	}
+	n := copy(p, f.buf)
+	f.buf = f.buf[n:]
+	for n < len(p) {
+		f.expander.Reset()
+		f.expander.Write(f.prev)
+		f.expander.Write(f.info)
+		f.ctr[0], f.ctr[1] = byte(f.counter>>8), byte(f.counter)
+		f.expander.Write(f.ctr[:])
+		f.prev = f.expander.Sum(f.prev[:0])
+		f.counter++
+
+		m := copy(p[n:], f.prev)
+		f.buf = f.prev[m:]
+		n += m
+	}
+	observeExpand(n)
+	return n, nil
+}
+
+func (f *wideReader) String() string {
+	return "hkdf.wideReader{<redacted>}"
+}
+
+func (f *wideReader) GoString() string {
+	return "&hkdf.wideReader{<redacted>}"
+}
@@ -0,0 +1,69 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestExpandWide(t *testing.T) {
+	for i, tt := range hkdfTests {
+		out := make([]byte, len(tt.out))
+		if _, err := io.ReadFull(ExpandWide(tt.hash, tt.prk, tt.info, 1), out); err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("test %d: incorrect output with a 1-byte counter: have %x, need %x", i, out, tt.out)
+		}
+	}
+
+	prk := Extract(sha256.New, []byte("secret"), nil)
+	var expected, prev []byte
+	for i := 1; i <= 3; i++ {
+		mac := hmac.New(sha256.New, prk)
+		mac.Write(prev)
+		mac.Write([]byte("info"))
+		mac.Write([]byte{0, byte(i)})
+		prev = mac.Sum(nil)
+		expected = append(expected, prev...)
+	}
+	r := ExpandWide(sha256.New, prk, []byte("info"), 2)
+	var out []byte
+	for _, n := range []int{1, 31, 33, 31} {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out = append(out, buf...)
+	}
+	if !bytes.Equal(out, expected) {
+		t.Errorf("incorrect output with a 2-byte counter: have %x, need %x", out, expected)
+	}
+
+	// The limit is 65535 blocks
+	limit := 0xffff * sha256.Size
+	r = ExpandWide(sha256.New, prk, nil, 2)
+	if n, err := r.Read(make([]byte, limit+1)); n != 0 || err != ErrEntropyLimit {
+		t.Errorf("read over the limit: n = %d, err = %v", n, err)
+	}
+	r = ExpandWide(sha256.New, prk, nil, 2)
+	if n, err := r.Read(make([]byte, limit)); n != limit || err != nil {
+		t.Errorf("read up to the limit: n = %d, err = %v", n, err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err != ErrEntropyLimit {
+		t.Errorf("have %v, need %v", err, ErrEntropyLimit)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a 3-byte counter")
+		}
+	}()
+	ExpandWide(sha256.New, prk, nil, 3)
+}
@@ -0,0 +1,75 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"hash"
+
+	"github.com/bored-engineer/crypto/sha3"
+)
+
+// NewXOF is like New, but replaces HMAC with a MAC built from the extendable
+// output function returned by newXOF, such as sha3.NewShake256, with outputLen
+// as the hash output size. The MAC of a message under a key is the first
+// outputLen bytes of the XOF output for the key, prefixed with its length as a
+// 32-bit big-endian integer, followed by the message. This prefix-keyed MAC is
+// sound for SHAKE, which unlike SHA-2 is not subject to length extension, and
+// is used for both the extract and the expand steps:
+//
+//	PRK  = MAC(salt, secret)
+//	T(i) = MAC(PRK, T(i-1) || info || i)
+//
+// A nil salt is replaced by outputLen zero bytes. At most 255*outputLen bytes
+// can be read, as with New.
+//
+// This construction is not defined by RFC 5869 or any other standard, and is
+// only meant for experimentation, such as with post-quantum protocols built
+// entirely on SHA-3. Its output is unrelated to that of New, and it has not
+// received the analysis of HKDF. The security level is bounded by outputLen,
+// which should be at least 32 bytes, and by the capacity of the XOF. Prefer
+// New, ExpandMAC with KMAC, or a standard SHA-3 based KDF where possible.
+//
+// NewXOF panics if outputLen is not positive.
+func NewXOF(newXOF func() sha3.ShakeHash, outputLen int, secret, salt, info []byte) *Reader {
+	if outputLen <= 0 {
+		panic("hkdf: invalid XOF output length")
+	}
+	mac := func(key []byte) hash.Hash { return newXOFMAC(newXOF, outputLen, key) }
+	prk := ExtractMAC(mac, secret, salt)
+	return ExpandMAC(mac, prk, info)
+}
+
+// xofMAC adapts the prefix-keyed MAC of NewXOF to hash.Hash.
+type xofMAC struct {
+	xof    sha3.ShakeHash
+	prefix []byte
+	size   int
+}
+
+func newXOFMAC(newXOF func() sha3.ShakeHash, size int, key []byte) *xofMAC {
+	m := &xofMAC{xof: newXOF(), prefix: appendDatalen(nil, key), size: size}
+	m.xof.Write(m.prefix)
+	return m
+}
+
+func (m *xofMAC) Write(p []byte) (int, error) { return m.xof.Write(p) }
+
+func (m *xofMAC) Sum(b []byte) []byte {
+	n := len(b)
+	b = append(b, make([]byte, m.size)...)
+	m.xof.Clone().Read(b[n:])
+	return b
+}
+
+func (m *xofMAC) Reset() {
+	m.xof.Reset()
+	m.xof.Write(m.prefix)
+}
+
+func (m *xofMAC) Size() int { return m.size }
+
+// BlockSize is not used by the Reader, and the rate of an arbitrary
+// sha3.ShakeHash is unknown, so it reports a byte-oriented hash.
+func (m *xofMAC) BlockSize() int { return 1 }
@@ -0,0 +1,67 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/bored-engineer/crypto/sha3"
+)
+
+// xofMACReference computes the MAC of NewXOF directly with SHAKE256.
+func xofMACReference(size int, key []byte, msg ...[]byte) []byte {
+	shake := sha3.NewShake256()
+	shake.Write([]byte{byte(len(key) >> 24), byte(len(key) >> 16), byte(len(key) >> 8), byte(len(key))})
+	shake.Write(key)
+	for _, m := range msg {
+		shake.Write(m)
+	}
+	out := make([]byte, size)
+	shake.Read(out)
+	return out
+}
+
+func TestNewXOF(t *testing.T) {
+	secret, info := []byte("secret"), []byte("info")
+	for _, size := range []int{16, 32, 64} {
+		for _, salt := range [][]byte{nil, []byte("salt")} {
+			key := salt
+			if key == nil {
+				key = make([]byte, size)
+			}
+			prk := xofMACReference(size, key, secret)
+			var expected, prev []byte
+			for i := byte(1); len(expected) < 3*size; i++ {
+				prev = xofMACReference(size, prk, prev, info, []byte{i})
+				expected = append(expected, prev...)
+			}
+
+			out := make([]byte, len(expected))
+			if _, err := io.ReadFull(NewXOF(sha3.NewShake256, size, secret, salt, info), out); err != nil {
+				t.Fatalf("size %d: unexpected error: %v", size, err)
+			}
+			if !bytes.Equal(out, expected) {
+				t.Errorf("size %d: have %x, need %x", size, out, expected)
+			}
+		}
+
+		r := NewXOF(sha3.NewShake128, size, secret, nil, info)
+		if _, err := io.ReadFull(r, make([]byte, 255*size)); err != nil {
+			t.Errorf("size %d: unexpected error: %v", size, err)
+		}
+		if _, err := r.Read(make([]byte, 1)); err != ErrEntropyLimit {
+			t.Errorf("size %d: have %v, need %v", size, err, ErrEntropyLimit)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a zero output length")
+		}
+	}()
+	NewXOF(sha3.NewShake256, 0, secret, nil, info)
+}